@@ -0,0 +1,76 @@
+package gocompute
+
+import (
+	"errors"
+)
+
+// An Ambiguity is a string with two distinct parse trees in a grammar.
+type Ambiguity struct {
+	String string
+	Trees  [2]*ParseTree
+}
+
+// Given a CFG g and a length bound maxLen, g.FindAmbiguity(maxLen) searches for a string of at most maxLen symbols
+// with two distinct parse trees, and returns the shortest one found, first in sorted order among those of its length,
+// together with two of its trees. It returns nil if every string of L(g) up to that length is unambiguous; g may
+// still be ambiguous on longer strings, and in general ambiguity is undecidable.
+//
+// Strings are explored breadth-first, extending only prefixes which the Earley parser can read, so the search never
+// leaves the prefixes of L(g). Each string of L(g) reached is parsed into a shared packed parse forest, in which a
+// node with more than one family marks an ambiguity without enumerating any trees. If the only second tree goes
+// around a cycle of the grammar, the returned tree goes around it once.
+func (g CFG) FindAmbiguity(maxLen int) (*Ambiguity, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	if maxLen < 0 {
+		return nil, errors.New("gocompute/cfg: length bound must not be negative")
+	}
+	terminals := sortedAlphabet(g.terminals)
+	level := []string{""}
+	for n := 0; n <= maxLen && len(level) > 0; n++ {
+		var next []string
+		for _, w := range level {
+			c := g.earley(w)
+			if len(c.sets[len(c.input)]) == 0 {
+				continue
+			}
+			if c.accepts() {
+				if a := g.ambiguity(w); a != nil {
+					return a, nil
+				}
+			}
+			for _, t := range terminals {
+				next = append(next, w+t)
+			}
+		}
+		level = next
+	}
+	return nil, nil
+}
+
+//returns two parse trees of w in g if it has more than one
+func (g CFG) ambiguity(w string) *Ambiguity {
+	f, err := g.ParseForest(w)
+	if err != nil || !f.IsAmbiguous() {
+		return nil
+	}
+	a := &Ambiguity{String: w}
+	found := 0
+	collect := func(t *ParseTree) bool {
+		a.Trees[found] = t
+		found++
+		return found < 2
+	}
+	f.Derivations(collect)
+	if found < 2 {
+		//the forest is ambiguous only through a cycle, so unroll it once
+		found = 0
+		f.derivations(2, collect)
+	}
+	if found < 2 {
+		return nil
+	}
+	return a
+}
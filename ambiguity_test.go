@@ -0,0 +1,59 @@
+package gocompute
+
+import (
+	"testing"
+)
+
+var findAmbiguityTests = []struct {
+	text       string
+	maxLen     int
+	w          string
+	trees      [2]string
+	descriptor string
+}{
+	{"E -> E + E | x", 5, "x+x+x", [2]string{"E(E(x) + E(E(x) + E(x)))", "E(E(E(x) + E(x)) + E(x))"}, "ambiguous grammar for sums"},
+	{"S -> i S | i S e S | a", 6, "iiaea", [2]string{"S(i S(i S(a) e S(a)))", "S(i S(i S(a)) e S(a))"}, "dangling else"},
+	{"S -> A | B\nA -> ε\nB -> ε", 3, "", [2]string{"S(A(ε))", "S(B(ε))"}, "two ways of deriving the empty string"},
+	{"S -> S | a", 3, "a", [2]string{"S(S(a))", "S(a)"}, "grammar which is ambiguous only through a cycle"},
+	{"S -> a S b | ε", 8, "", [2]string{}, "unambiguous grammar for a^n b^n"},
+	{"E -> E + x | x", 7, "", [2]string{}, "unambiguous left recursive grammar"},
+	{"E -> E + E | x", 4, "", [2]string{}, "ambiguous grammar whose shortest ambiguous string is too long"},
+}
+
+func TestCFGFindAmbiguity(t *testing.T) {
+	for _, test := range findAmbiguityTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		a, err := g.FindAmbiguity(test.maxLen)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if test.trees[0] == "" {
+			if a != nil {
+				t.Error("On test: " + test.descriptor + ", error: unexpected ambiguity on string " + a.String)
+			}
+			continue
+		}
+		if a == nil {
+			t.Error("On test: " + test.descriptor + ", error: expected ambiguity on string " + test.w)
+			continue
+		}
+		if a.String != test.w || a.Trees[0].String() != test.trees[0] || a.Trees[1].String() != test.trees[1] {
+			t.Error("On test: " + test.descriptor + ", error: unexpected ambiguity on string " + a.String + " with trees " + a.Trees[0].String() + " and " + a.Trees[1].String())
+		}
+		for _, tree := range a.Trees {
+			if tree.Yield() != a.String || !usesProductionsOf(g, tree) {
+				t.Error("On test: " + test.descriptor + ", error: " + tree.String() + " is not a parse tree of " + a.String)
+			}
+		}
+	}
+
+	g, _ := ParseCFG("S -> a")
+	if _, err := g.FindAmbiguity(-1); err == nil {
+		t.Error("On test: negative length bound, error: should fail")
+	}
+}
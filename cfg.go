@@ -0,0 +1,331 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A Production is a rule of a context-free grammar, rewriting the variable Head to the sequence of symbols Body.
+// An empty Body is an ε-production.
+type Production struct {
+	Head string
+	Body []string
+}
+
+// String returns the production in the same syntax ParseCFG reads, such as "S -> a S b".
+func (p Production) String() string {
+	if len(p.Body) == 0 {
+		return p.Head + " -> ε"
+	}
+	return p.Head + " -> " + strings.Join(p.Body, " ")
+}
+
+//productions contain a slice, so they cannot be map keys themselves
+func (p Production) key() string {
+	return p.Head + "\x00" + strings.Join(p.Body, "\x00")
+}
+
+// Internal representation of a CFG
+type CFG struct {
+	variables   mapset.Set
+	terminals   mapset.Set
+	productions []Production
+	start       string
+}
+
+// Constructor method for creating a CFG. Takes as input a set of strings naming the variables, a set of
+// single-character strings representing the terminals, a list of productions and a start variable. Returns a pointer
+// to the newly created CFG and an error, which is non-nil if the input was improperly formatted.
+//
+// Variables and terminals must be disjoint, every production must rewrite a variable to a sequence of variables and
+// terminals, and the start variable must be a variable. Duplicate productions are dropped.
+func NewCFG(variables,
+	terminals mapset.Set,
+	productions []Production,
+	start string) (*CFG, error) {
+
+	g := &CFG{variables, terminals, nil, start}
+	seen := map[string]bool{}
+	for _, p := range productions {
+		if !seen[p.key()] {
+			seen[p.key()] = true
+			g.productions = append(g.productions, Production{p.Head, append([]string(nil), p.Body...)})
+		}
+	}
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Variables returns a copy of the set of variables of g.
+func (g CFG) Variables() mapset.Set {
+	return g.variables.Clone()
+}
+
+// Terminals returns a copy of the set of terminals of g.
+func (g CFG) Terminals() mapset.Set {
+	return g.terminals.Clone()
+}
+
+// Productions returns a copy of the productions of g, in order.
+func (g CFG) Productions() []Production {
+	productions := make([]Production, len(g.productions))
+	for i, p := range g.productions {
+		productions[i] = Production{p.Head, append([]string(nil), p.Body...)}
+	}
+	return productions
+}
+
+// Start returns the start variable of g.
+func (g CFG) Start() string {
+	return g.start
+}
+
+// Checks to make sure a given CFG g is properly formatted with correct input data.
+func (g CFG) CheckCFG() (bool, error) {
+	//check that variables are non-empty strings without whitespace
+	for _, elem := range g.variables.ToSlice() {
+		if reflect.TypeOf(elem).Kind() != reflect.String {
+			return false, errors.New("gocompute/cfg: set of variables contains non-string type")
+		}
+		v := elem.(string)
+		if v == "" || strings.IndexFunc(v, unicode.IsSpace) >= 0 {
+			return false, errors.New("gocompute/cfg: invalid variable name \"" + v + "\"")
+		}
+	}
+
+	//check that terminals are single characters, and distinct from variables
+	if !checkSymbols(g.terminals) {
+		return false, errors.New("gocompute/cfg: terminals contain a symbol which is not a single-character string")
+	}
+	if g.variables.Intersect(g.terminals).Cardinality() != 0 {
+		return false, errors.New("gocompute/cfg: variables and terminals are not disjoint")
+	}
+
+	if !g.variables.Contains(g.start) {
+		return false, errors.New("gocompute/cfg: start variable not in set of variables")
+	}
+
+	//check that every production rewrites a variable to declared symbols
+	for _, p := range g.productions {
+		if !g.variables.Contains(p.Head) {
+			return false, errors.New("gocompute/cfg: production " + p.String() + " does not rewrite a variable")
+		}
+		for _, symbol := range p.Body {
+			if !g.variables.Contains(symbol) && !g.terminals.Contains(symbol) {
+				return false, errors.New("gocompute/cfg: production " + p.String() + " uses undeclared symbol " + symbol)
+			}
+		}
+	}
+	return true, nil
+}
+
+//splits the right hand side of a rule into tokens. quoted tokens are returned
+//with their quotes so that the caller can tell them apart.
+func tokenizeRule(text string) ([]string, error) {
+	var tokens []string
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated quote")
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// ParseCFG reads a context-free grammar written in a simple BNF syntax, one rule per line:
+//
+//	S -> a S b | ε
+//	<list> ::= <item> ',' <list> | <item>
+//
+// Symbols are separated by whitespace, alternatives by |, and ε stands for the empty body. Every symbol which
+// appears on the left of a rule is a variable, and the start variable is the left hand side of the first rule. Any
+// other symbol is a terminal, and must be a single character, optionally quoted with ' or " so that characters such
+// as | and ε can be used. An unquoted symbol of more than one character which is never defined is an error, since it
+// is most likely a misspelled variable. A rule with nothing on its right, such as A ->, declares a variable with no
+// productions. Blank lines and lines starting with # are ignored.
+func ParseCFG(text string) (*CFG, error) {
+	type rule struct {
+		line int
+		head string
+		body []string
+	}
+	var rules []rule
+	var heads []string
+	variables := mapset.NewSet()
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prefix := "gocompute/cfg: line " + strconv.Itoa(n+1) + ": "
+		arrow := "->"
+		i := strings.Index(line, arrow)
+		if j := strings.Index(line, "::="); j >= 0 && (i < 0 || j < i) {
+			arrow, i = "::=", j
+		}
+		if i < 0 {
+			return nil, errors.New(prefix + "missing -> or ::=")
+		}
+		head := strings.TrimSpace(line[:i])
+		if head == "" || strings.IndexFunc(head, unicode.IsSpace) >= 0 || strings.ContainsAny(head, "'\"|") {
+			return nil, errors.New(prefix + "invalid variable name \"" + head + "\"")
+		}
+		tokens, err := tokenizeRule(line[i+len(arrow):])
+		if err != nil {
+			return nil, errors.New(prefix + err.Error())
+		}
+		variables.Add(head)
+		heads = append(heads, head)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		var body []string
+		for k := 0; k <= len(tokens); k++ {
+			if k < len(tokens) && tokens[k] != "|" {
+				body = append(body, tokens[k])
+				continue
+			}
+			if len(body) == 0 {
+				return nil, errors.New(prefix + "empty alternative, use ε for the empty body")
+			}
+			rules = append(rules, rule{n + 1, head, body})
+			body = nil
+		}
+	}
+	if len(heads) == 0 {
+		return nil, errors.New("gocompute/cfg: grammar has no rules")
+	}
+
+	//now that every variable is known, classify the remaining symbols
+	terminals := mapset.NewSet()
+	var productions []Production
+	for _, r := range rules {
+		prefix := "gocompute/cfg: line " + strconv.Itoa(r.line) + ": "
+		p := Production{r.head, nil}
+		for _, token := range r.body {
+			switch {
+			case token == "ε":
+				if len(r.body) != 1 {
+					return nil, errors.New(prefix + "ε must appear alone in an alternative")
+				}
+			case token[0] == '\'' || token[0] == '"':
+				symbol := token[1 : len(token)-1]
+				if len([]rune(symbol)) != 1 {
+					return nil, errors.New(prefix + "quoted terminal " + token + " must be a single character")
+				}
+				if variables.Contains(symbol) {
+					return nil, errors.New(prefix + "terminal " + token + " is also a variable")
+				}
+				terminals.Add(symbol)
+				p.Body = append(p.Body, symbol)
+			case variables.Contains(token):
+				p.Body = append(p.Body, token)
+			case len([]rune(token)) == 1:
+				terminals.Add(token)
+				p.Body = append(p.Body, token)
+			default:
+				return nil, errors.New(prefix + "undeclared variable " + token)
+			}
+		}
+		productions = append(productions, p)
+	}
+	return NewCFG(variables, terminals, productions, heads[0])
+}
+
+//returns the variables of g in a fixed order: the start variable, then the heads of
+//productions in order of appearance, then any variables without productions sorted
+func (g CFG) orderedVariables() []string {
+	seen := map[string]bool{g.start: true}
+	order := []string{g.start}
+	for _, p := range g.productions {
+		if !seen[p.Head] {
+			seen[p.Head] = true
+			order = append(order, p.Head)
+		}
+	}
+	var rest []string
+	for _, v := range sortedAlphabet(g.variables) {
+		if !seen[v] {
+			rest = append(rest, v)
+		}
+	}
+	return append(order, rest...)
+}
+
+//returns the indices of the productions of g for each variable
+func (g CFG) productionsByHead() map[string][]int {
+	byHead := map[string][]int{}
+	for i, p := range g.productions {
+		byHead[p.Head] = append(byHead[p.Head], i)
+	}
+	return byHead
+}
+
+//quotes a terminal when it could otherwise be misread by ParseCFG
+func formatTerminal(t string) string {
+	r := []rune(t)[0]
+	switch {
+	case r == '"':
+		return "'\"'"
+	case r == '\'' || r == '|' || r == '#' || t == "ε" || unicode.IsSpace(r) || !unicode.IsPrint(r):
+		return "\"" + t + "\""
+	}
+	return t
+}
+
+// String returns g in the syntax read by ParseCFG, with one line per variable listing all of its alternatives. The
+// start variable comes first, and other variables follow in the order their first production appears. Variables
+// with no productions follow, sorted, each declared by a line with nothing after the arrow.
+func (g CFG) String() string {
+	byHead := g.productionsByHead()
+	var lines []string
+	for _, v := range g.orderedVariables() {
+		var alternatives []string
+		for _, i := range byHead[v] {
+			var symbols []string
+			for _, symbol := range g.productions[i].Body {
+				if g.terminals.Contains(symbol) {
+					symbol = formatTerminal(symbol)
+				}
+				symbols = append(symbols, symbol)
+			}
+			if len(symbols) == 0 {
+				symbols = []string{"ε"}
+			}
+			alternatives = append(alternatives, strings.Join(symbols, " "))
+		}
+		if len(alternatives) == 0 {
+			lines = append(lines, v+" ->")
+			continue
+		}
+		lines = append(lines, v+" -> "+strings.Join(alternatives, " | "))
+	}
+	return strings.Join(lines, "\n")
+}
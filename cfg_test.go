@@ -0,0 +1,98 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+)
+
+var parseCFGTests = []struct {
+	text        string
+	variables   int
+	terminals   int
+	productions int
+	start       string
+	printed     string
+	descriptor  string
+}{
+	{"S -> a S b | ε", 1, 2, 2, "S", "S -> a S b | ε", "grammar for a^n b^n"},
+	{"S -> S S | ( S ) | ε", 1, 2, 3, "S", "S -> S S | ( S ) | ε", "grammar for balanced parentheses"},
+	{"# expressions\nE -> E + T | T\n\nT -> T * F | F\nF -> ( E ) | x", 3, 5, 6, "E", "E -> E + T | T\nT -> T * F | F\nF -> ( E ) | x", "grammar for arithmetic expressions, with a comment and blank line"},
+	{"<list> ::= <item> ',' <list> | <item>\n<item> ::= x", 2, 2, 3, "<list>", "<list> -> <item> , <list> | <item>\n<item> -> x", "grammar using ::= and quoted terminals"},
+	{"S -> '|' S | \"'\" | \"ε\" | '\"'", 1, 4, 4, "S", "S -> \"|\" S | \"'\" | \"ε\" | '\"'", "grammar with terminals needing quotes"},
+	{"S -> A\nA -> a\nS -> b", 2, 2, 3, "S", "S -> A | b\nA -> a", "grammar with alternatives for a variable split across lines"},
+	{"S -> a | A B\nB ->\nA -> a", 3, 1, 3, "S", "S -> a | A B\nA -> a\nB ->", "grammar declaring a variable with no productions"},
+}
+
+func TestParseCFG(t *testing.T) {
+	for _, test := range parseCFGTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if g.variables.Cardinality() != test.variables || g.terminals.Cardinality() != test.terminals || len(g.productions) != test.productions {
+			t.Error("On test: " + test.descriptor + ", error: expected " + strconv.Itoa(test.variables) + " variables, " + strconv.Itoa(test.terminals) + " terminals and " + strconv.Itoa(test.productions) + " productions")
+		}
+		if g.start != test.start {
+			t.Error("On test: " + test.descriptor + ", error: start variable should be " + test.start + ", got " + g.start)
+		}
+		if g.String() != test.printed {
+			t.Error("On test: " + test.descriptor + ", error: grammar printed as\n" + g.String() + "\nexpected\n" + test.printed)
+		}
+		//the printed grammar must parse back to the same grammar
+		h, err := ParseCFG(g.String())
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: printed grammar does not parse: " + err.Error())
+			continue
+		}
+		if h.String() != g.String() || !h.terminals.Equal(g.terminals) {
+			t.Error("On test: " + test.descriptor + ", error: printed grammar does not parse back to the same grammar")
+		}
+	}
+}
+
+var parseCFGErrorTests = []struct {
+	text       string
+	descriptor string
+}{
+	{"", "empty grammar"},
+	{"S a b", "missing arrow"},
+	{"S -> a | | b", "empty alternative"},
+	{"S -> a |", "empty last alternative"},
+	{"S -> a ε", "ε alongside other symbols"},
+	{"S -> Sx", "undeclared variable"},
+	{"S -> 'ab'", "multi-character quoted terminal"},
+	{"S -> 'a", "unterminated quote"},
+	{"S -> 'A'\nA -> a", "quoted terminal which is also a variable"},
+	{"a b -> c", "variable name with whitespace"},
+}
+
+func TestParseCFGErrors(t *testing.T) {
+	for _, test := range parseCFGErrorTests {
+		if _, err := ParseCFG(test.text); err == nil {
+			t.Error("On test: " + test.descriptor + ", error: grammar should not parse")
+		}
+	}
+}
+
+func TestNewCFG(t *testing.T) {
+	productions := []Production{{"S", []string{"a", "S", "b"}}, {"S", nil}, {"S", nil}}
+	g, err := NewCFG(mapset.NewSet("S"), mapset.NewSet("a", "b"), productions, "S")
+	if err != nil {
+		t.Error("On test: valid grammar, error: " + err.Error())
+		t.FailNow()
+	}
+	if len(g.productions) != 2 {
+		t.Error("On test: valid grammar, error: duplicate productions should be dropped")
+	}
+	if _, err := NewCFG(mapset.NewSet("S"), mapset.NewSet("a"), []Production{{"S", []string{"b"}}}, "S"); err == nil {
+		t.Error("On test: undeclared terminal, error: grammar should be rejected")
+	}
+	if _, err := NewCFG(mapset.NewSet("S", "a"), mapset.NewSet("a"), nil, "S"); err == nil {
+		t.Error("On test: overlapping variables and terminals, error: grammar should be rejected")
+	}
+	if _, err := NewCFG(mapset.NewSet("S"), mapset.NewSet("a"), nil, "T"); err == nil {
+		t.Error("On test: undeclared start variable, error: grammar should be rejected")
+	}
+}
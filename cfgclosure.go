@@ -0,0 +1,210 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"strconv"
+)
+
+//returns a copy of g whose variables are renamed, by adding primes, to avoid the
+//symbols in taken. the new names are added to taken.
+func (g CFG) renameApart(taken mapset.Set) *CFG {
+	names := map[string]string{}
+	for _, v := range g.orderedVariables() {
+		names[v] = freshState(taken, v)
+		taken.Add(names[v])
+	}
+	h := &CFG{mapset.NewSet(), g.terminals, nil, names[g.start]}
+	for _, v := range g.orderedVariables() {
+		h.variables.Add(names[v])
+	}
+	for _, p := range g.productions {
+		q := Production{names[p.Head], make([]string, len(p.Body))}
+		for i, symbol := range p.Body {
+			if name, ok := names[symbol]; ok {
+				symbol = name
+			}
+			q.Body[i] = symbol
+		}
+		h.productions = append(h.productions, q)
+	}
+	return h
+}
+
+//renames the variables of g1 and g2 apart from each other and from all terminals, and
+//joins them under a fresh start variable whose productions are made by join from the
+//two old start variables
+func combineCFGs(g1, g2 *CFG, join func(s1, s2 string) [][]string) (*CFG, error) {
+	ans, err := g1.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	ans, err = g2.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	taken := g1.terminals.Union(g2.terminals)
+	h1 := g1.renameApart(taken)
+	h2 := g2.renameApart(taken)
+	start := freshState(taken, "S")
+	var productions []Production
+	for _, body := range join(h1.start, h2.start) {
+		productions = append(productions, Production{start, body})
+	}
+	productions = append(append(productions, h1.productions...), h2.productions...)
+	variables := h1.variables.Union(h2.variables)
+	variables.Add(start)
+	return NewCFG(variables, g1.terminals.Union(g2.terminals), productions, start)
+}
+
+// Given CFGs g1 and g2, g1.Union(g2) returns a pointer to a new CFG recognizing the union of L(g1) and L(g2). The
+// variables of g1 and g2 are renamed apart where they clash, by adding primes, and a fresh start variable derives
+// either old start variable.
+func (g1 CFG) Union(g2 *CFG) (*CFG, error) {
+	return combineCFGs(&g1, g2, func(s1, s2 string) [][]string {
+		return [][]string{{s1}, {s2}}
+	})
+}
+
+// Given CFGs g1 and g2, g1.Concatenation(g2) returns a pointer to a new CFG recognizing the strings xy, where x is in
+// L(g1) and y is in L(g2). Variables are renamed apart as for Union, and a fresh start variable derives the start
+// variable of g1 followed by that of g2.
+func (g1 CFG) Concatenation(g2 *CFG) (*CFG, error) {
+	return combineCFGs(&g1, g2, func(s1, s2 string) [][]string {
+		return [][]string{{s1, s2}}
+	})
+}
+
+// Given a CFG g, g.Star() returns a pointer to a new CFG recognizing the concatenations of any number of strings in
+// L(g), including none. A fresh start variable S has the productions S -> T S and S -> ε, where T is the start
+// variable of g.
+func (g CFG) Star() (*CFG, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	start := freshState(g.variables.Union(g.terminals), "S")
+	productions := append([]Production{{start, []string{g.start, start}}, {start, nil}}, g.productions...)
+	variables := g.variables.Clone()
+	variables.Add(start)
+	return NewCFG(variables, g.terminals.Clone(), productions, start)
+}
+
+// Given a CFG g, g.Reverse() returns a pointer to a new CFG recognizing the reverses of the strings in L(g). It has
+// the same variables as g, and the body of each production reversed.
+func (g CFG) Reverse() (*CFG, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	var productions []Production
+	for _, p := range g.productions {
+		body := make([]string, len(p.Body))
+		for i, symbol := range p.Body {
+			body[len(body)-1-i] = symbol
+		}
+		productions = append(productions, Production{p.Head, body})
+	}
+	return NewCFG(g.variables.Clone(), g.terminals.Clone(), productions, g.start)
+}
+
+// Given a CFG g and a DFA d, g.IntersectRegular(d) returns a pointer to a new CFG recognizing the intersection of L(g)
+// and L(d), using the triple construction. Long bodies of g are first split as by Binarize. Each variable [p,A,q] of the
+// new grammar then derives the strings which A derives in g and which take d from state p to state q, where states
+// are numbered in breadth-first order from the start state, 0. A fresh start variable derives [0,S,f] for the start
+// variable S of g and each accept state f.
+//
+// Only variables reachable from the start variable are built, and useless symbols are then removed as by
+// RemoveUseless, so terminals of g which d never reads on the way to an accept state are dropped.
+func (g CFG) IntersectRegular(d *DFA) (*CFG, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	ans, err = d.CheckDFA()
+	if ans == false && err != nil {
+		return nil, errors.New("gocompute/dfa: invalid DFA: " + err.Error())
+	}
+	order, _ := reachableStates(d)
+	index := map[interface{}]int{}
+	for i, state := range order {
+		index[state] = i
+	}
+
+	b := g.rules()
+	b.binarize()
+	byHead := map[string][]Production{}
+	for _, rule := range b.rules {
+		byHead[rule.Head] = append(byHead[rule.Head], rule.Production)
+	}
+
+	start := freshState(b.variables.Union(b.terminals), "S")
+	r := &cfgRules{mapset.NewSet(start), g.terminals.Clone(), nil, start}
+	seen := map[string]bool{}
+	var queue [][3]int
+	heads := map[[3]int]string{}
+	symbolIndex := map[string]int{}
+	for i, v := range b.orderedVariables() {
+		symbolIndex[v] = i
+	}
+	variable := func(p int, v string, q int) string {
+		key := [3]int{p, symbolIndex[v], q}
+		if name, ok := heads[key]; ok {
+			return name
+		}
+		name := "[" + strconv.Itoa(p) + "," + v + "," + strconv.Itoa(q) + "]"
+		heads[key] = name
+		r.variables.Add(name)
+		queue = append(queue, key)
+		return name
+	}
+	//returns the state d reaches from state p on terminal a, or -1 if d cannot read a
+	step := func(p int, a string) int {
+		if !d.alphabet.Contains(a) {
+			return -1
+		}
+		return index[d.transition(order[p], a)]
+	}
+
+	for _, f := range order {
+		if d.accept.Contains(f) {
+			r.add(seen, cfgRule{Production{start, []string{variable(0, g.start, index[f])}}, nil})
+		}
+	}
+	variables := b.orderedVariables()
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		p, v, q := key[0], variables[key[1]], key[2]
+		head := heads[key]
+		for _, production := range byHead[v] {
+			//choose the state reached after each symbol of the body in turn
+			var expand func(body []string, state, i int)
+			expand = func(body []string, state, i int) {
+				if i == len(production.Body) {
+					if state == q {
+						r.add(seen, cfgRule{Production{head, append([]string{}, body...)}, nil})
+					}
+					return
+				}
+				symbol := production.Body[i]
+				if b.terminals.Contains(symbol) {
+					if next := step(state, symbol); next >= 0 {
+						expand(append(body, symbol), next, i+1)
+					}
+					return
+				}
+				if i == len(production.Body)-1 {
+					expand(append(body, variable(state, symbol, q)), q, i+1)
+					return
+				}
+				for next := range order {
+					expand(append(body, variable(state, symbol, next)), next, i+1)
+				}
+			}
+			expand([]string{}, p, 0)
+		}
+	}
+	r.removeUseless()
+	return r.grammar()
+}
@@ -0,0 +1,127 @@
+package gocompute
+
+import (
+	"strconv"
+	"testing"
+)
+
+var cfgClosureTests = []struct {
+	text1, text2 string
+	alphabet     []string
+	descriptor   string
+}{
+	{"S -> a S b | ε", "S -> b S a | ε", []string{"a", "b"}, "grammars with the same variable names"},
+	{"S -> a T\nT -> b | ε", "T -> a | b T", []string{"a", "b"}, "grammars where variables of one clash with the other's start variable"},
+	{"S -> a S | b", "a -> b a | c", []string{"a", "b", "c"}, "grammars where a variable of one is a terminal of the other"},
+}
+
+func TestCFGClosure(t *testing.T) {
+	for _, test := range cfgClosureTests {
+		g1, err := ParseCFG(test.text1)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		g2, err := ParseCFG(test.text2)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		union, err := g1.Union(g2)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		concatenation, err := g1.Concatenation(g2)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		star, err := g1.Star()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		reverse, err := g2.Reverse()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		in1 := func(w string) bool {
+			ans, _ := g1.Recognize(w)
+			return ans
+		}
+		in2 := func(w string) bool {
+			ans, _ := g2.Recognize(w)
+			return ans
+		}
+		for _, w := range allStrings(test.alphabet, 6) {
+			inConcatenation := false
+			//inStar[i] reports whether w[:i] is a concatenation of strings in L(g1)
+			inStar := []bool{true}
+			for i := 0; i <= len(w); i++ {
+				inConcatenation = inConcatenation || (in1(w[:i]) && in2(w[i:]))
+				if i > 0 {
+					inStar = append(inStar, false)
+					for j := 0; j < i; j++ {
+						inStar[i] = inStar[i] || (inStar[j] && in1(w[j:i]))
+					}
+				}
+			}
+			reversed := ""
+			for _, r := range w {
+				reversed = string(r) + reversed
+			}
+			expected := []struct {
+				g    *CFG
+				want bool
+				name string
+			}{
+				{union, in1(w) || in2(w), "union"},
+				{concatenation, inConcatenation, "concatenation"},
+				{star, inStar[len(w)], "star"},
+				{reverse, in2(reversed), "reverse"},
+			}
+			for _, e := range expected {
+				if got, _ := e.g.Recognize(w); got != e.want {
+					t.Error("On test: " + test.descriptor + ", error: " + e.name + " should have answered " + strconv.FormatBool(e.want) + " to string " + w)
+				}
+			}
+		}
+	}
+}
+
+var cfgIntersectRegularTests = []struct {
+	d          *DFA
+	err        error
+	descriptor string
+}{
+	{d1, d1err, "DFA accepting strings with an even number of ones"},
+	{d3, d3err, "DFA with struct states accepting strings with an even number of ones"},
+	{d4, d4err, "DFA accepting strings with an odd number of zeros"},
+	{d7, d7err, "DFA accepting all strings"},
+	{d8, d8err, "DFA accepting no strings"},
+	{d9, d9err, "DFA accepting strings containing 11"},
+}
+
+func TestCFGIntersectRegular(t *testing.T) {
+	g, _ := ParseCFG("S -> 0 S 1 | S S | ε")
+	for _, test := range cfgIntersectRegularTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		h, err := g.IntersectRegular(test.d)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		for _, w := range allBinaryStrings(8) {
+			inG, _ := g.Recognize(w)
+			inD, _ := test.d.Simulate(w)
+			if got, _ := h.Recognize(w); got != (inG && inD) {
+				t.Error("On test: " + test.descriptor + ", error: intersection should have answered " + strconv.FormatBool(inG && inD) + " to string " + w)
+			}
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"strings"
+)
+
+//returns, for each generating variable of g, a parse tree of least height deriving a
+//terminal string from it. a variable missing from the map is not generating.
+func (g CFG) generatingTrees() map[string]*ParseTree {
+	trees := map[string]*ParseTree{}
+	for changed := true; changed; {
+		changed = false
+		//trees found in this round are only used in the next, so that heights stay least
+		found := map[string]*ParseTree{}
+		for _, p := range g.productions {
+			if _, ok := trees[p.Head]; ok {
+				continue
+			}
+			if _, ok := found[p.Head]; ok {
+				continue
+			}
+			children := []*ParseTree{}
+			for _, symbol := range p.Body {
+				if g.terminals.Contains(symbol) {
+					children = append(children, &ParseTree{symbol, nil})
+					continue
+				}
+				child, ok := trees[symbol]
+				if !ok {
+					children = nil
+					break
+				}
+				children = append(children, child)
+			}
+			if children != nil {
+				found[p.Head] = &ParseTree{p.Head, children}
+			}
+		}
+		for v, t := range found {
+			trees[v] = t
+			changed = true
+		}
+	}
+	return trees
+}
+
+// Given a CFG g, g.IsEmpty() reports whether L(g) is empty. If it is not, it also returns a witness: a parse tree of
+// least height for some string in L(g), whose Yield is the string.
+func (g CFG) IsEmpty() (bool, *ParseTree, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return false, nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	if t, ok := g.generatingTrees()[g.start]; ok {
+		return false, t, nil
+	}
+	return true, nil, nil
+}
+
+// A CFGPumping is a decomposition uvxyz of a string, with vy non-empty, as in the pumping lemma for context-free
+// languages: the start variable derives u A z, and the variable A derives both v A y and x, so that uv^ixy^iz is in
+// the language for every i >= 0.
+type CFGPumping struct {
+	U, V, X, Y, Z string
+	Variable      string
+}
+
+// Pump returns the string uv^ixy^iz.
+func (p CFGPumping) Pump(i int) string {
+	return p.U + strings.Repeat(p.V, i) + p.X + strings.Repeat(p.Y, i) + p.Z
+}
+
+//an edge from a variable to one of the variables in the body of one of its rules,
+//with the shortest strings derived from the symbols to its left and right
+type cfgEdge struct {
+	to          string
+	left, right string
+}
+
+//returns the shortest path of edges from one variable to another, with at least one
+//edge, or nil if there is none
+func shortestEdgePath(edges map[string][]cfgEdge, from, to string) []cfgEdge {
+	parent := map[string]cfgEdge{}
+	prev := map[string]string{}
+	//from is left unvisited when it is also the goal, so that a cycle can return to it
+	visited := map[string]bool{from: from != to}
+	queue := []string{from}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, e := range edges[v] {
+			if visited[e.to] {
+				continue
+			}
+			visited[e.to] = true
+			parent[e.to], prev[e.to] = e, v
+			if e.to != to {
+				queue = append(queue, e.to)
+				continue
+			}
+			path := []cfgEdge{parent[to]}
+			for w := prev[to]; w != from; w = prev[w] {
+				path = append([]cfgEdge{parent[w]}, path...)
+			}
+			return path
+		}
+	}
+	return nil
+}
+
+//concatenates the left and right contexts along a path
+func pathContexts(path []cfgEdge) (left, right string) {
+	for _, e := range path {
+		left += e.left
+		right = e.right + right
+	}
+	return left, right
+}
+
+// Given a CFG g, g.IsFinite() reports whether L(g) is finite. If it is not, it also returns a certificate: a
+// self-embedding variable A, derivations S =>* u A z, A =>* v A y and A =>* x, and the decomposition uvxyz.
+//
+// The search works on g.ToCNF(), where L(g) is infinite exactly when some useful variable derives itself. Variables
+// of g are tried first, in the order in which String prints them, so A is a variable of g whenever one embeds itself;
+// otherwise it is one of the helper variables of the conversion. Each of u, v, x, y and z is chosen as short as the
+// search allows.
+func (g CFG) IsFinite() (bool, *CFGPumping, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return false, nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	c := g.cnfRules()
+
+	//shortest strings derived from each symbol
+	shortest := map[string]string{}
+	for _, t := range c.terminals.ToSlice() {
+		shortest[t.(string)] = t.(string)
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range c.rules {
+			w, ok := "", true
+			for _, symbol := range rule.Body {
+				s, found := shortest[symbol]
+				ok = ok && found
+				w += s
+			}
+			if old, found := shortest[rule.Head]; ok && (!found || len(w) < len(old) || (len(w) == len(old) && w < old)) {
+				shortest[rule.Head] = w
+				changed = true
+			}
+		}
+	}
+
+	edges := map[string][]cfgEdge{}
+	for _, rule := range c.rules {
+		if len(rule.Body) != 2 {
+			continue
+		}
+		b, d := rule.Body[0], rule.Body[1]
+		edges[rule.Head] = append(edges[rule.Head], cfgEdge{b, "", shortest[d]}, cfgEdge{d, shortest[b], ""})
+	}
+
+	candidates := g.orderedVariables()
+	for _, v := range c.orderedVariables() {
+		if !g.variables.Contains(v) {
+			candidates = append(candidates, v)
+		}
+	}
+	for _, v := range candidates {
+		if !c.variables.Contains(v) {
+			continue
+		}
+		cycle := shortestEdgePath(edges, v, v)
+		if cycle == nil {
+			continue
+		}
+		p := &CFGPumping{X: shortest[v], Variable: v}
+		p.V, p.Y = pathContexts(cycle)
+		if v != c.start {
+			p.U, p.Z = pathContexts(shortestEdgePath(edges, c.start, v))
+		}
+		return false, p, nil
+	}
+	return true, nil, nil
+}
+
+// A SymbolReport lists the useless symbols of a CFG. NonGenerating holds the variables which derive no terminal
+// string. Unreachable holds the variables and terminals which appear in no sentential form derived from the start
+// variable, once productions using non-generating variables are dropped. Productions holds the productions which
+// RemoveUseless drops because of them. Variables are listed in the order in which String prints them, and
+// terminals after them in sorted order.
+type SymbolReport struct {
+	NonGenerating []string
+	Unreachable   []string
+	Productions   []Production
+}
+
+// Given a CFG g, g.UselessSymbols() returns a report of the non-generating and unreachable symbols of g, and of the
+// productions which use them. The report is empty exactly when every symbol of g is useful, in which case
+// g.RemoveUseless() leaves g unchanged.
+func (g CFG) UselessSymbols() (*SymbolReport, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	report := &SymbolReport{}
+	trees := g.generatingTrees()
+	generating := g.terminals.Clone()
+	for v := range trees {
+		generating.Add(v)
+	}
+	useful := make([]bool, len(g.productions))
+	var productions []Production
+	for i, p := range g.productions {
+		useful[i] = generating.Contains(p.Head)
+		for _, symbol := range p.Body {
+			useful[i] = useful[i] && generating.Contains(symbol)
+		}
+		if useful[i] {
+			productions = append(productions, p)
+		}
+	}
+
+	reachable := mapset.NewSet(g.start)
+	for changed := true; changed; {
+		changed = false
+		for _, p := range productions {
+			if !reachable.Contains(p.Head) {
+				continue
+			}
+			for _, symbol := range p.Body {
+				if reachable.Add(symbol) {
+					changed = true
+				}
+			}
+		}
+	}
+
+	for _, v := range g.orderedVariables() {
+		switch {
+		case !generating.Contains(v):
+			report.NonGenerating = append(report.NonGenerating, v)
+		case !reachable.Contains(v):
+			report.Unreachable = append(report.Unreachable, v)
+		}
+	}
+	for _, t := range sortedAlphabet(g.terminals) {
+		if !reachable.Contains(t) {
+			report.Unreachable = append(report.Unreachable, t)
+		}
+	}
+	for i, p := range g.productions {
+		if !useful[i] || !reachable.Contains(p.Head) {
+			report.Productions = append(report.Productions, p)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,124 @@
+package gocompute
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var cfgDecisionTests = []struct {
+	text       string
+	empty      bool
+	finite     bool
+	descriptor string
+}{
+	{"S -> a S b | ε", false, false, "grammar for a^n b^n"},
+	{"S -> a S | S b", true, true, "grammar whose productions never terminate"},
+	{"S -> A B\nA -> a | b\nB -> c | ε", false, true, "grammar for a finite language"},
+	{"S -> A\nA -> B | a\nB -> A", false, true, "grammar for a finite language with a cycle of unit productions"},
+	{"S -> A | x\nA -> A A A\nB -> b B | b", false, true, "grammar whose only recursion is useless"},
+	{"S -> a T\nT -> S A | b\nA -> ε", false, false, "grammar where self-embedding passes through a nullable variable"},
+	{"S -> ε", false, true, "grammar for the empty string"},
+}
+
+func TestCFGDecisions(t *testing.T) {
+	for _, test := range cfgDecisionTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		empty, witness, err := g.IsEmpty()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if empty != test.empty {
+			t.Error("On test: " + test.descriptor + ", error: emptiness should be " + strconv.FormatBool(test.empty))
+		}
+		if !empty {
+			if ans, _ := g.Recognize(witness.Yield()); !ans {
+				t.Error("On test: " + test.descriptor + ", error: witness " + witness.String() + " does not derive a string of the language")
+			}
+			if !usesProductionsOf(g, witness) {
+				t.Error("On test: " + test.descriptor + ", error: witness " + witness.String() + " is not a parse tree of the grammar")
+			}
+		}
+
+		finite, pumping, err := g.IsFinite()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if finite != test.finite {
+			t.Error("On test: " + test.descriptor + ", error: finiteness should be " + strconv.FormatBool(test.finite))
+		}
+		if finite {
+			continue
+		}
+		if pumping.V+pumping.Y == "" {
+			t.Error("On test: " + test.descriptor + ", error: pumped parts are empty")
+		}
+		for i := 0; i <= 3; i++ {
+			if ans, _ := g.Recognize(pumping.Pump(i)); !ans {
+				t.Error("On test: " + test.descriptor + ", error: pumped string " + pumping.Pump(i) + " is not in the language")
+			}
+		}
+	}
+}
+
+func TestCFGIsFiniteVariable(t *testing.T) {
+	g, _ := ParseCFG("S -> x A y\nA -> ( A ) | z")
+	_, pumping, err := g.IsFinite()
+	if err != nil {
+		t.Error("On test: self-embedding variable, error: " + err.Error())
+		t.FailNow()
+	}
+	if pumping == nil || pumping.Variable != "A" {
+		t.Error("On test: self-embedding variable, error: expected the certificate to embed A")
+		t.FailNow()
+	}
+	if pumping.Pump(2) != "x((z))y" {
+		t.Error("On test: self-embedding variable, error: expected x((z))y, got " + pumping.Pump(2))
+	}
+}
+
+var uselessSymbolsTests = []struct {
+	text          string
+	nonGenerating []string
+	unreachable   []string
+	productions   []string
+	descriptor    string
+}{
+	{"S -> a S b | ε", nil, nil, nil, "grammar with no useless symbols"},
+	{"S -> a S | S b", []string{"S"}, []string{"a", "b"}, []string{"S -> a S", "S -> S b"}, "grammar whose productions never terminate"},
+	{"S -> A B | a\nA -> a A\nB -> b\nC -> c", []string{"A"}, []string{"B", "C", "b", "c"}, []string{"S -> A B", "A -> a A", "B -> b", "C -> c"}, "grammar where a variable becomes unreachable once non-generating productions are dropped"},
+}
+
+func TestCFGUselessSymbols(t *testing.T) {
+	for _, test := range uselessSymbolsTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		report, err := g.UselessSymbols()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		var productions []string
+		for _, p := range report.Productions {
+			productions = append(productions, p.String())
+		}
+		if strings.Join(report.NonGenerating, " ") != strings.Join(test.nonGenerating, " ") {
+			t.Error("On test: " + test.descriptor + ", error: unexpected non-generating symbols " + strings.Join(report.NonGenerating, " "))
+		}
+		if strings.Join(report.Unreachable, " ") != strings.Join(test.unreachable, " ") {
+			t.Error("On test: " + test.descriptor + ", error: unexpected unreachable symbols " + strings.Join(report.Unreachable, " "))
+		}
+		if strings.Join(productions, "\n") != strings.Join(test.productions, "\n") {
+			t.Error("On test: " + test.descriptor + ", error: unexpected useless productions " + strings.Join(productions, ", "))
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//assigns each variable of g a single-character stack symbol. variables which are
+//already a single character stand for themselves, and the others get characters
+//from the private use area which are not symbols of g.
+func (g CFG) stackSymbols() map[string]string {
+	symbols := map[string]string{}
+	used := g.terminals.Union(g.variables)
+	next := ''
+	for _, v := range g.orderedVariables() {
+		if len([]rune(v)) == 1 {
+			symbols[v] = v
+			continue
+		}
+		for used.Contains(string(next)) {
+			next++
+		}
+		symbols[v] = string(next)
+		used.Add(string(next))
+	}
+	return symbols
+}
+
+// Given a CFG g, g.ToPDA() returns a pointer to a new PDA recognizing L(g), using the standard top-down construction.
+// The PDA has a single state and accepts by empty stack. It starts with the start variable on its stack, and at each
+// step either replaces a variable on top of the stack by the body of one of its productions, or reads an input
+// symbol matching the terminal on top of the stack and pops it. Variables whose names are longer than one character
+// are represented on the stack by fresh single-character symbols.
+//
+// The PDA guesses a leftmost derivation, so for a left recursive grammar its stack can grow without reading any
+// input, and PDA.Simulate may give up with an error on strings outside the language.
+func (g CFG) ToPDA() (*PDA, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	symbols := g.stackSymbols()
+	pushes := map[string][]string{}
+	stackAlphabet := g.terminals.Clone()
+	for _, v := range g.orderedVariables() {
+		stackAlphabet.Add(symbols[v])
+	}
+	for _, p := range g.productions {
+		push := ""
+		for _, symbol := range p.Body {
+			if s, ok := symbols[symbol]; ok {
+				symbol = s
+			}
+			push += symbol
+		}
+		pushes[symbols[p.Head]] = append(pushes[symbols[p.Head]], push)
+	}
+
+	terminals := g.terminals
+	transition := func(state, input, stackSymbol string) (moves mapset.Set) {
+		moves = mapset.NewSet()
+		switch {
+		case input == "" && stackSymbol != "":
+			for _, push := range pushes[stackSymbol] {
+				moves.Add(PDAMove{"q", push})
+			}
+		case input != "" && input == stackSymbol && terminals.Contains(input):
+			moves.Add(PDAMove{"q", ""})
+		}
+		return moves
+	}
+	return NewPDAWithAcceptance(mapset.NewSet("q"), g.terminals.Clone(), stackAlphabet, transition, "q", symbols[g.start], mapset.NewSet(), AcceptEmptyStack)
+}
+
+//escapes a state or stack symbol for the name of a triple variable, so that names
+//of different triples differ and ParseCFG can read them
+func tripleComponent(s string) string {
+	var escaped []string
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == ',' || r == '[' || r == ']':
+			escaped = append(escaped, "\\"+string(r))
+		case strings.ContainsRune("'\"|:>", r) || unicode.IsSpace(r) || !unicode.IsPrint(r):
+			//: and > could otherwise start ::= or end ->
+			hex := strconv.FormatInt(int64(r), 16)
+			if r > 0xffff {
+				escaped = append(escaped, "\\U"+strings.Repeat("0", 8-len(hex))+hex)
+			} else {
+				escaped = append(escaped, "\\u"+strings.Repeat("0", 4-len(hex))+hex)
+			}
+		default:
+			escaped = append(escaped, string(r))
+		}
+	}
+	return strings.Join(escaped, "")
+}
+
+// Given a PDA p, p.ToCFG() returns a pointer to a new CFG recognizing L(p), using the triple construction. p is first
+// converted to accept by empty stack, behind a fresh bottom-of-stack marker so that its stack is never empty before it
+// accepts. Each variable [q,X,r] of the grammar then derives exactly the strings which p can read going from state q
+// to state r while popping X off its stack, with a fresh start variable S deriving [s,Z,r] for the start state s, the
+// initial stack symbol Z and every state r. A move which reads a, pops X and pushes Y1 ... Yk from q to q1 gives the
+// productions [q,X,r] -> a [q1,Y1,r1] [r1,Y2,r2] ... [rk-1,Yk,r] for every choice of states r1, ..., rk-1, r, and a
+// move which leaves the stack alone is treated as popping and pushing back each possible top symbol.
+//
+// Only variables reachable from S are built, and variables which derive no string are then removed, along with
+// anything else RemoveUseless would drop. Variables are named after their triples, with backslashes, commas and
+// brackets in states and stack symbols escaped by a backslash, and characters ParseCFG does not allow in a variable
+// name written as \u or \U codes, so that the grammar's String can be read back. S is primed as often as needed to
+// keep it apart from the input symbols.
+func (p PDA) ToCFG() (*CFG, error) {
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/pda: invalid PDA: " + err.Error())
+	}
+	//converting to final state first ensures the empty stack conversion adds its marker
+	final, err := p.ToFinalStateAcceptance()
+	if err != nil {
+		return nil, err
+	}
+	q, err := final.ToEmptyStackAcceptance()
+	if err != nil {
+		return nil, err
+	}
+
+	states := sortedAlphabet(q.states)
+	inputs := append([]string{""}, sortedAlphabet(q.alphabet)...)
+	name := func(from, top, to string) string {
+		return "[" + tripleComponent(from) + "," + tripleComponent(top) + "," + tripleComponent(to) + "]"
+	}
+
+	//triples are longer than any input symbol, so only the start variable can clash with one
+	start := freshState(q.alphabet, "S")
+	r := &cfgRules{mapset.NewSet(start), q.alphabet.Clone(), nil, start}
+	seen := map[string]bool{}
+	var queue [][3]string
+	variable := func(from, top, to string) string {
+		v := name(from, top, to)
+		if !r.variables.Contains(v) {
+			r.variables.Add(v)
+			queue = append(queue, [3]string{from, top, to})
+		}
+		return v
+	}
+	for _, to := range states {
+		r.add(seen, cfgRule{Production{start, []string{variable(q.start, q.stackStart, to)}}, nil})
+	}
+	for len(queue) > 0 {
+		from, top, to := queue[0][0], queue[0][1], queue[0][2]
+		queue = queue[1:]
+		head := name(from, top, to)
+		for _, a := range inputs {
+			var moves []PDAMove
+			if popping := q.transition(from, a, top); popping != nil {
+				for elem := range popping.Iter() {
+					moves = append(moves, elem.(PDAMove))
+				}
+			}
+			if keeping := q.transition(from, a, ""); keeping != nil {
+				for elem := range keeping.Iter() {
+					move := elem.(PDAMove)
+					moves = append(moves, PDAMove{move.State, move.Push + top})
+				}
+			}
+			for _, move := range moves {
+				push := []rune(move.Push)
+				prefix := []string{}
+				if a != "" {
+					prefix = append(prefix, a)
+				}
+				if len(push) == 0 {
+					if move.State == to {
+						r.add(seen, cfgRule{Production{head, prefix}, nil})
+					}
+					continue
+				}
+				//choose the state reached after popping each pushed symbol in turn
+				var expand func(body []string, state string, i int)
+				expand = func(body []string, state string, i int) {
+					if i == len(push)-1 {
+						body = append(append([]string{}, body...), variable(state, string(push[i]), to))
+						r.add(seen, cfgRule{Production{head, body}, nil})
+						return
+					}
+					for _, mid := range states {
+						expand(append(append([]string{}, body...), variable(state, string(push[i]), mid)), mid, i+1)
+					}
+				}
+				expand(prefix, move.State, 0)
+			}
+		}
+	}
+	r.removeUseless()
+	return r.grammar()
+}
@@ -0,0 +1,192 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+)
+
+//returns every string over alphabet of length at most n, shortest first
+func allStrings(alphabet []string, n int) []string {
+	words := []string{""}
+	for i := 0; i < len(words); i++ {
+		if len([]rune(words[i])) < n {
+			for _, a := range alphabet {
+				words = append(words, words[i]+a)
+			}
+		}
+	}
+	return words
+}
+
+var cfgToPDATests = []struct {
+	text       string
+	alphabet   []string
+	descriptor string
+}{
+	{"S -> a S b | ε", []string{"a", "b"}, "grammar for a^n b^n"},
+	{"S -> a S a | b S b | a | b | ε", []string{"a", "b"}, "grammar for palindromes"},
+	{"E -> T Rest\nRest -> + T Rest | ε\nT -> x | ( E )", []string{"x", "+", "(", ")"}, "grammar for sums with variables longer than one character"},
+	{"S -> A B\nA -> a A | ε\nB -> b B c | ε", []string{"a", "b", "c"}, "grammar for a^i b^j c^j"},
+}
+
+func TestCFGToPDA(t *testing.T) {
+	for _, test := range cfgToPDATests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		p, err := g.ToPDA()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		for _, w := range allStrings(test.alphabet, 6) {
+			want, _ := g.Recognize(w)
+			got, err := p.Simulate(w)
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", while testing string " + w + ", error: " + err.Error())
+			}
+			if got != want {
+				t.Error("On test: " + test.descriptor + ", error: PDA should have answered " + strconv.FormatBool(want) + " to string " + w)
+			}
+		}
+	}
+}
+
+var pdaToCFGTests = []struct {
+	p          *PDA
+	err        error
+	alphabet   []string
+	descriptor string
+}{
+	{p1, p1err, []string{"a", "b"}, "PDA accepting a^n b^n by final state"},
+	{p2, p2err, []string{"a", "b"}, "PDA accepting even-length palindromes"},
+	{p3, p3err, []string{"a"}, "PDA with an ε-loop on the input"},
+	{p4, p4err, []string{"a", "b"}, "PDA accepting a^n b^n by empty stack"},
+	{p5, p5err, []string{"a", "b"}, "PDA accepting a^n b^n by final state and empty stack"},
+}
+
+func TestPDAToCFG(t *testing.T) {
+	for _, test := range pdaToCFGTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		g, err := test.p.ToCFG()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		for _, w := range allStrings(test.alphabet, 6) {
+			want, _ := test.p.Simulate(w)
+			got, _ := g.Recognize(w)
+			if got != want {
+				t.Error("On test: " + test.descriptor + ", error: grammar should have answered " + strconv.FormatBool(want) + " to string " + w)
+			}
+		}
+	}
+}
+
+func TestCFGToPDAToCFG(t *testing.T) {
+	g, _ := ParseCFG("S -> ( S ) S | ε")
+	p, err := g.ToPDA()
+	if err != nil {
+		t.Error("On test: round trip of balanced parentheses, error: " + err.Error())
+		t.FailNow()
+	}
+	h, err := p.ToCFG()
+	if err != nil {
+		t.Error("On test: round trip of balanced parentheses, error: " + err.Error())
+		t.FailNow()
+	}
+	for _, w := range allStrings([]string{"(", ")"}, 8) {
+		want, _ := g.Recognize(w)
+		got, _ := h.Recognize(w)
+		if got != want {
+			t.Error("On test: round trip of balanced parentheses, error: grammar should have answered " + strconv.FormatBool(want) + " to string " + w)
+		}
+	}
+}
+
+func TestPDAToCFGStartSymbolInAlphabet(t *testing.T) {
+	g, _ := ParseCFG("A -> S A b | ε")
+	p, err := g.ToPDA()
+	if err != nil {
+		t.Error("On test: PDA reading S, error: " + err.Error())
+		t.FailNow()
+	}
+	h, err := p.ToCFG()
+	if err != nil {
+		t.Error("On test: PDA reading S, error: " + err.Error())
+		t.FailNow()
+	}
+	if h.Start() == "S" || h.Variables().Contains("S") {
+		t.Error("On test: PDA reading S, error: start variable should not be the terminal S")
+	}
+	for _, w := range allStrings([]string{"S", "b"}, 6) {
+		want, _ := g.Recognize(w)
+		got, _ := h.Recognize(w)
+		if got != want {
+			t.Error("On test: PDA reading S, error: grammar should have answered " + strconv.FormatBool(want) + " to string " + w)
+		}
+	}
+}
+
+func makeAwkwardNamesPDA() (*PDA, error) {
+	//like makeAnBnPDA, with states and stack symbols ParseCFG cannot take in a variable name
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "q 0" && input == "a" && stackSymbol != "":
+			return pdaMoves(PDAMove{"q 0", "\"" + stackSymbol})
+		case state == "q 0" && input == "" && stackSymbol != "":
+			return pdaMoves(PDAMove{"q|1->", stackSymbol})
+		case state == "q|1->" && input == "b" && stackSymbol == "\"":
+			return pdaMoves(PDAMove{"q|1->", ""})
+		case state == "q|1->" && input == "" && stackSymbol == ",":
+			return pdaMoves(PDAMove{"q,2]\\", ","})
+		}
+		return nil
+	}
+	return NewPDA(mapset.NewSet("q 0", "q|1->", "q,2]\\"), mapset.NewSet("a", "b"), mapset.NewSet(",", "\""), transition, "q 0", ",", mapset.NewSet("q,2]\\"))
+}
+
+func TestPDAToCFGString(t *testing.T) {
+	awkward, awkwardErr := makeAwkwardNamesPDA()
+	var tests = []struct {
+		p          *PDA
+		err        error
+		descriptor string
+	}{
+		{p1, p1err, "PDA accepting a^n b^n by final state"},
+		{p4, p4err, "PDA accepting a^n b^n by empty stack"},
+		{awkward, awkwardErr, "PDA with quotes, bars, spaces, commas and arrows in its names"},
+	}
+	for _, test := range tests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			continue
+		}
+		g, err := test.p.ToCFG()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		h, err := ParseCFG(g.String())
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: printed grammar does not parse: " + err.Error())
+			continue
+		}
+		if h.String() != g.String() || h.Variables().Cardinality() != g.Variables().Cardinality() {
+			t.Error("On test: " + test.descriptor + ", error: printed grammar does not parse back to the same grammar")
+		}
+		for _, w := range allStrings([]string{"a", "b"}, 6) {
+			want, _ := test.p.Simulate(w)
+			got, _ := h.Recognize(w)
+			if got != want {
+				t.Error("On test: " + test.descriptor + ", error: grammar read back should have answered " + strconv.FormatBool(want) + " to string " + w)
+			}
+		}
+	}
+}
@@ -0,0 +1,597 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//the normal form transformations remember, for every rule they produce, which
+//derivation in the grammar they started from it stands for. that origin is a
+//forest of derivation trees whose leaves include one hole per position of the
+//rule's body, in order and all under the same parent. substituting the subtree
+//derived from each body position into its hole gives a derivation in the
+//original grammar, which is how parse trees are mapped back from CNF.
+type derivation struct {
+	symbol   string
+	children []*derivation
+	hole     int
+}
+
+type cfgRule struct {
+	Production
+	origin []*derivation
+}
+
+//a grammar in the middle of being transformed
+type cfgRules struct {
+	variables mapset.Set
+	terminals mapset.Set
+	rules     []cfgRule
+	start     string
+}
+
+func holeNode(symbol string, hole int) *derivation {
+	return &derivation{symbol, nil, hole}
+}
+
+//returns a copy of forest with every hole replaced by the forest fill returns for it
+func fillHoles(forest []*derivation, fill func(hole *derivation) []*derivation) []*derivation {
+	var filled []*derivation
+	for _, node := range forest {
+		if node.hole >= 0 {
+			filled = append(filled, fill(node)...)
+			continue
+		}
+		filled = append(filled, &derivation{node.symbol, fillHoles(node.children, fill), -1})
+	}
+	return filled
+}
+
+//renders a forest as a string, to compare origins
+func forestKey(forest []*derivation) string {
+	var parts []string
+	for _, node := range forest {
+		switch {
+		case node.hole >= 0:
+			parts = append(parts, "#"+strconv.Itoa(node.hole))
+		case node.children == nil:
+			parts = append(parts, node.symbol)
+		default:
+			parts = append(parts, node.symbol+"("+forestKey(node.children)+")")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+//the identity transformation: each production stands for itself
+func (g CFG) rules() *cfgRules {
+	r := &cfgRules{g.variables.Clone(), g.terminals.Clone(), nil, g.start}
+	for _, p := range g.productions {
+		root := &derivation{p.Head, []*derivation{}, -1}
+		for i, symbol := range p.Body {
+			root.children = append(root.children, holeNode(symbol, i))
+		}
+		r.rules = append(r.rules, cfgRule{p, []*derivation{root}})
+	}
+	return r
+}
+
+func (r *cfgRules) grammar() (*CFG, error) {
+	productions := make([]Production, len(r.rules))
+	for i, rule := range r.rules {
+		productions[i] = rule.Production
+	}
+	return NewCFG(r.variables, r.terminals, productions, r.start)
+}
+
+//adds a rule unless an identical rule with the same origin is already present
+func (r *cfgRules) add(seen map[string]bool, rule cfgRule) {
+	key := rule.key() + "\x00" + forestKey(rule.origin)
+	if !seen[key] {
+		seen[key] = true
+		r.rules = append(r.rules, rule)
+	}
+}
+
+//returns base, or base followed by a number, whichever is not already a symbol of r
+func (r *cfgRules) freshVariable(base string) string {
+	name := base
+	for i := 1; r.variables.Contains(name) || r.terminals.Contains(name); i++ {
+		name = base + strconv.Itoa(i)
+	}
+	r.variables.Add(name)
+	return name
+}
+
+func (r *cfgRules) addStart() {
+	start := r.freshVariable(r.start + "0")
+	r.rules = append([]cfgRule{{Production{start, []string{r.start}}, []*derivation{holeNode(r.start, 0)}}}, r.rules...)
+	r.start = start
+}
+
+func (r *cfgRules) separateTerminals() {
+	helper := map[string]string{}
+	var added []cfgRule
+	for i, rule := range r.rules {
+		if len(rule.Body) < 2 {
+			continue
+		}
+		body := append([]string(nil), rule.Body...)
+		for j, symbol := range body {
+			if !r.terminals.Contains(symbol) {
+				continue
+			}
+			if _, ok := helper[symbol]; !ok {
+				base := "T_" + symbol
+				if c := []rune(symbol)[0]; !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+					base = "T_" + strconv.Itoa(int(c))
+				}
+				helper[symbol] = r.freshVariable(base)
+				added = append(added, cfgRule{Production{helper[symbol], []string{symbol}}, []*derivation{holeNode(symbol, 0)}})
+			}
+			body[j] = helper[symbol]
+		}
+		r.rules[i].Body = body
+	}
+	r.rules = append(r.rules, added...)
+}
+
+//finds the list of siblings holding the holes of an origin
+func holeSiblings(forest []*derivation) []*derivation {
+	for _, node := range forest {
+		if node.hole >= 0 {
+			return forest
+		}
+	}
+	for _, node := range forest {
+		if siblings := holeSiblings(node.children); siblings != nil {
+			return siblings
+		}
+	}
+	return nil
+}
+
+//returns a copy of forest in which the list of siblings holding the holes is replaced by replace(siblings)
+func replaceHoleSiblings(forest []*derivation, replace func(siblings []*derivation) []*derivation) []*derivation {
+	for _, node := range forest {
+		if node.hole >= 0 {
+			return replace(forest)
+		}
+	}
+	copied := make([]*derivation, len(forest))
+	for i, node := range forest {
+		copied[i] = node
+		if holeSiblings(node.children) != nil {
+			copied[i] = &derivation{node.symbol, replaceHoleSiblings(node.children, replace), -1}
+		}
+	}
+	return copied
+}
+
+func (r *cfgRules) binarize() {
+	var rules []cfgRule
+	for _, rule := range r.rules {
+		if len(rule.Body) < 3 {
+			rules = append(rules, rule)
+			continue
+		}
+		//A -> s0 s1 ... sk becomes A -> s0 X1, X1 -> s1 X2, ..., Xk-1 -> sk-1 sk. the
+		//origin is cut just before each hole, so that whatever lies between two holes
+		//(a derivation of ε, for instance) goes with the hole before it.
+		k := len(rule.Body)
+		siblings := holeSiblings(rule.origin)
+		holeAt := make([]int, k)
+		for i, node := range siblings {
+			if node.hole >= 0 {
+				holeAt[node.hole] = i
+			}
+		}
+		segment := func(from, to int, renumber func(hole int) int) []*derivation {
+			var seg []*derivation
+			for _, node := range siblings[from:to] {
+				if node.hole >= 0 {
+					node = holeNode(node.symbol, renumber(node.hole))
+				}
+				seg = append(seg, node)
+			}
+			return seg
+		}
+
+		helpers := make([]string, k-1)
+		for i := 1; i < k-1; i++ {
+			helpers[i] = r.freshVariable("X")
+		}
+		head := cfgRule{Production{rule.Head, []string{rule.Body[0], helpers[1]}}, nil}
+		head.origin = replaceHoleSiblings(rule.origin, func(siblings []*derivation) []*derivation {
+			joined := append([]*derivation(nil), siblings[:holeAt[1]]...)
+			joined = append(joined, holeNode(helpers[1], 1))
+			return append(joined, siblings[holeAt[k-1]+1:]...)
+		})
+		rules = append(rules, head)
+		for i := 1; i < k-2; i++ {
+			origin := segment(holeAt[i], holeAt[i+1], func(int) int { return 0 })
+			origin = append(origin, holeNode(helpers[i+1], 1))
+			rules = append(rules, cfgRule{Production{helpers[i], []string{rule.Body[i], helpers[i+1]}}, origin})
+		}
+		origin := segment(holeAt[k-2], holeAt[k-1]+1, func(hole int) int { return hole - (k - 2) })
+		rules = append(rules, cfgRule{Production{helpers[k-2], []string{rule.Body[k-2], rule.Body[k-1]}}, origin})
+	}
+	r.rules = rules
+}
+
+//returns, for each nullable variable, one derivation of ε from it, using the first
+//of its rules found to derive ε
+func (r *cfgRules) nullable() map[string][]*derivation {
+	eps := map[string][]*derivation{}
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range r.rules {
+			if _, done := eps[rule.Head]; done {
+				continue
+			}
+			all := true
+			for _, symbol := range rule.Body {
+				if _, ok := eps[symbol]; !ok {
+					all = false
+					break
+				}
+			}
+			if all {
+				eps[rule.Head] = fillHoles(rule.origin, func(hole *derivation) []*derivation {
+					return eps[hole.symbol]
+				})
+				changed = true
+			}
+		}
+	}
+	return eps
+}
+
+func (r *cfgRules) removeEpsilon() {
+	eps := r.nullable()
+	seen := map[string]bool{}
+	rules := r.rules
+	r.rules = nil
+	for _, rule := range rules {
+		var nullablePositions []int
+		for i, symbol := range rule.Body {
+			if _, ok := eps[symbol]; ok {
+				nullablePositions = append(nullablePositions, i)
+			}
+		}
+		//every subset of the nullable positions may be dropped
+		for mask := 0; mask < 1<<uint(len(nullablePositions)); mask++ {
+			dropped := map[int]bool{}
+			for b, i := range nullablePositions {
+				if mask&(1<<uint(b)) != 0 {
+					dropped[i] = true
+				}
+			}
+			var body []string
+			renumber := map[int]int{}
+			for i, symbol := range rule.Body {
+				if !dropped[i] {
+					renumber[i] = len(body)
+					body = append(body, symbol)
+				}
+			}
+			if len(body) == 0 {
+				continue
+			}
+			origin := fillHoles(rule.origin, func(hole *derivation) []*derivation {
+				if dropped[hole.hole] {
+					return eps[hole.symbol]
+				}
+				return []*derivation{holeNode(hole.symbol, renumber[hole.hole])}
+			})
+			r.add(seen, cfgRule{Production{rule.Head, body}, origin})
+		}
+	}
+	if origin, ok := eps[r.start]; ok {
+		r.add(seen, cfgRule{Production{r.start, nil}, origin})
+	}
+}
+
+func (r *cfgRules) isUnit(rule cfgRule) bool {
+	return len(rule.Body) == 1 && r.variables.Contains(rule.Body[0])
+}
+
+func (r *cfgRules) removeUnit() {
+	byHead := map[string][]cfgRule{}
+	for _, rule := range r.rules {
+		byHead[rule.Head] = append(byHead[rule.Head], rule)
+	}
+	seen := map[string]bool{}
+	var rules []cfgRule
+	add := func(rule cfgRule) {
+		key := rule.key() + "\x00" + forestKey(rule.origin)
+		if !seen[key] {
+			seen[key] = true
+			rules = append(rules, rule)
+		}
+	}
+
+	//follow every chain of unit rules from each variable that does not repeat a
+	//variable, composing origins along the way, and attach the non-unit rules at
+	//its end. chains that revisit a variable derive nothing new.
+	var follow func(head string, origin []*derivation, visited map[string]bool, at string)
+	follow = func(head string, origin []*derivation, visited map[string]bool, at string) {
+		for _, rule := range byHead[at] {
+			composed := fillHoles(origin, func(hole *derivation) []*derivation {
+				return rule.origin
+			})
+			if !r.isUnit(rule) {
+				add(cfgRule{Production{head, rule.Body}, composed})
+				continue
+			}
+			next := rule.Body[0]
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			follow(head, composed, visited, next)
+			delete(visited, next)
+		}
+	}
+	for _, v := range r.orderedVariables() {
+		follow(v, []*derivation{holeNode(v, 0)}, map[string]bool{v: true}, v)
+	}
+	r.rules = rules
+}
+
+func (r *cfgRules) orderedVariables() []string {
+	g := CFG{r.variables, r.terminals, nil, r.start}
+	for _, rule := range r.rules {
+		g.productions = append(g.productions, rule.Production)
+	}
+	return g.orderedVariables()
+}
+
+func (r *cfgRules) removeUseless() {
+	//a symbol is generating if it derives some terminal string
+	generating := r.terminals.Clone()
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range r.rules {
+			if generating.Contains(rule.Head) {
+				continue
+			}
+			all := true
+			for _, symbol := range rule.Body {
+				if !generating.Contains(symbol) {
+					all = false
+					break
+				}
+			}
+			if all {
+				generating.Add(rule.Head)
+				changed = true
+			}
+		}
+	}
+	var rules []cfgRule
+	for _, rule := range r.rules {
+		all := generating.Contains(rule.Head)
+		for _, symbol := range rule.Body {
+			all = all && generating.Contains(symbol)
+		}
+		if all {
+			rules = append(rules, rule)
+		}
+	}
+
+	//a symbol is reachable if it appears in some sentential form derived from the start
+	reachable := mapset.NewSet(r.start)
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range rules {
+			if !reachable.Contains(rule.Head) {
+				continue
+			}
+			for _, symbol := range rule.Body {
+				if reachable.Add(symbol) {
+					changed = true
+				}
+			}
+		}
+	}
+	r.rules = nil
+	for _, rule := range rules {
+		if reachable.Contains(rule.Head) {
+			r.rules = append(r.rules, rule)
+		}
+	}
+	r.variables = r.variables.Intersect(reachable)
+	r.terminals = r.terminals.Intersect(reachable)
+}
+
+//the full chain of transformations to Chomsky normal form. terminals and long
+//bodies are dealt with before ε-productions, so that removing ε-productions only
+//ever sees bodies of length two and cannot blow up exponentially.
+func (g CFG) cnfRules() *cfgRules {
+	r := g.rules()
+	r.addStart()
+	r.separateTerminals()
+	r.binarize()
+	r.removeEpsilon()
+	r.removeUnit()
+	r.removeUseless()
+	return r
+}
+
+// Given a CFG g, g.AddStartVariable() returns a pointer to a new CFG with a fresh start variable whose only
+// production rewrites it to the start variable of g. The new start variable appears in no body. This is the first
+// step of g.ToCNF().
+func (g CFG) AddStartVariable() (*CFG, error) {
+	r := g.rules()
+	r.addStart()
+	return r.grammar()
+}
+
+// Given a CFG g, g.SeparateTerminals() returns a pointer to a new CFG in which every terminal a appearing in a body
+// of two or more symbols is replaced by a fresh variable with the single production T_a -> a.
+func (g CFG) SeparateTerminals() (*CFG, error) {
+	r := g.rules()
+	r.separateTerminals()
+	return r.grammar()
+}
+
+// Given a CFG g, g.Binarize() returns a pointer to a new CFG in which every body of three or more symbols is split
+// into a chain of bodies of two symbols, using fresh variables.
+func (g CFG) Binarize() (*CFG, error) {
+	r := g.rules()
+	r.binarize()
+	return r.grammar()
+}
+
+// Given a CFG g, g.RemoveEpsilon() returns a pointer to a new CFG recognizing the same language without
+// ε-productions, except for a production rewriting the start variable to ε if ε is in L(g). Every production whose
+// body contains nullable variables is replaced by all of its variants with some of those variables left out.
+func (g CFG) RemoveEpsilon() (*CFG, error) {
+	r := g.rules()
+	r.removeEpsilon()
+	return r.grammar()
+}
+
+// Given a CFG g, g.RemoveUnit() returns a pointer to a new CFG recognizing the same language with no unit
+// productions, those rewriting a variable to a single variable. Each variable instead gets the non-unit productions
+// of every variable it derives through unit productions.
+func (g CFG) RemoveUnit() (*CFG, error) {
+	r := g.rules()
+	r.removeUnit()
+	return r.grammar()
+}
+
+// Given a CFG g, g.RemoveUseless() returns a pointer to a new CFG recognizing the same language without useless
+// symbols: first every production using a symbol which derives no terminal string is dropped, then every production
+// of a variable which cannot be reached from the start variable. The start variable is always kept.
+func (g CFG) RemoveUseless() (*CFG, error) {
+	r := g.rules()
+	r.removeUseless()
+	return r.grammar()
+}
+
+// Given a CFG g, g.ToCNF() returns a pointer to a new CFG in Chomsky normal form recognizing L(g). Every production
+// of the new grammar has the form A -> B C, where B and C are variables other than the start variable, or A -> a for a
+// terminal a, or is the production S -> ε for the start variable S, present when ε is in L(g). The steps are, in
+// order, AddStartVariable, SeparateTerminals, Binarize, RemoveEpsilon, RemoveUnit and RemoveUseless.
+func (g CFG) ToCNF() (*CFG, error) {
+	return g.cnfRules().grammar()
+}
+
+// Given a CFG g, g.ToGNF() returns a pointer to a new CFG in Greibach normal form recognizing L(g). Every production
+// of the new grammar has the form A -> a B1 ... Bk, where a is a terminal and B1, ..., Bk are variables other than the
+// start variable, or is the production S -> ε for the start variable S, present when ε is in L(g).
+//
+// The grammar is first put in Chomsky normal form. Its variables A1, ..., An are then ordered, and productions are
+// rewritten from A1 upwards until every Ai -> Aj ... has j > i, eliminating immediate left recursion with fresh
+// variables as it appears. Substituting back down from An then makes every body start with a terminal.
+func (g CFG) ToGNF() (*CFG, error) {
+	c := g.cnfRules()
+	order := c.orderedVariables()
+	rank := map[string]int{}
+	for i, v := range order {
+		rank[v] = i
+	}
+	bodies := map[string][][]string{}
+	nullable := false
+	for _, rule := range c.rules {
+		if rule.Head == c.start && len(rule.Body) == 0 {
+			nullable = true
+			continue
+		}
+		bodies[rule.Head] = append(bodies[rule.Head], rule.Body)
+	}
+
+	//rewrites every body of head starting with a variable accepted by lead, using
+	//that variable's productions
+	substitute := func(head string, lead func(v string) bool) {
+		var rewritten [][]string
+		for _, body := range bodies[head] {
+			if !lead(body[0]) {
+				rewritten = append(rewritten, body)
+				continue
+			}
+			for _, prefix := range bodies[body[0]] {
+				rewritten = append(rewritten, append(append([]string(nil), prefix...), body[1:]...))
+			}
+		}
+		bodies[head] = rewritten
+	}
+
+	var extra []string
+	for i, v := range order {
+		//substituting may expose a new Aj with j < i, so repeat until none is left
+		for again := true; again; {
+			again = false
+			for _, body := range bodies[v] {
+				if j, ok := rank[body[0]]; ok && j < i {
+					again = true
+					break
+				}
+			}
+			substitute(v, func(w string) bool {
+				j, ok := rank[w]
+				return ok && j < i
+			})
+		}
+
+		//eliminate immediate left recursion A -> A α | β, giving A -> β | β Z and Z -> α | α Z
+		var alphas, betas [][]string
+		for _, body := range bodies[v] {
+			if body[0] == v {
+				alphas = append(alphas, body[1:])
+			} else {
+				betas = append(betas, body)
+			}
+		}
+		if len(alphas) == 0 {
+			continue
+		}
+		if len(betas) == 0 {
+			//v derives no terminal string. RemoveUseless would have dropped it.
+			return nil, errors.New("gocompute/cfg: variable " + v + " is left recursive and non-generating")
+		}
+		z := c.freshVariable("Z_" + v)
+		extra = append(extra, z)
+		bodies[v] = nil
+		for _, beta := range betas {
+			bodies[v] = append(bodies[v], beta, append(append([]string(nil), beta...), z))
+		}
+		for _, alpha := range alphas {
+			bodies[z] = append(bodies[z], alpha, append(append([]string(nil), alpha...), z))
+		}
+	}
+
+	//every Ai -> Aj ... now has j > i, and An starts with a terminal
+	isVariable := func(v string) bool {
+		return c.variables.Contains(v)
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		substitute(order[i], isVariable)
+	}
+	for _, z := range extra {
+		substitute(z, isVariable)
+	}
+
+	var productions []Production
+	for _, v := range append(order, extra...) {
+		for _, body := range bodies[v] {
+			if c.variables.Contains(body[0]) {
+				return nil, errors.New("gocompute/cfg: production for " + v + " does not start with a terminal")
+			}
+			productions = append(productions, Production{v, body})
+		}
+	}
+	if nullable {
+		productions = append(productions, Production{c.start, nil})
+	}
+	gnf, err := NewCFG(c.variables, c.terminals, productions, c.start)
+	if err != nil {
+		return nil, err
+	}
+	return gnf.RemoveUseless()
+}
@@ -0,0 +1,144 @@
+package gocompute
+
+import (
+	"strconv"
+	"testing"
+)
+
+var normalFormTests = []struct {
+	text       string
+	testWords  map[string]bool
+	descriptor string
+}{
+	{"S -> a S b | ε", map[string]bool{"": true, "ab": true, "aabb": true, "a": false, "abb": false, "ba": false}, "grammar for a^n b^n"},
+	{"S -> S S | ( S ) | ε", map[string]bool{"": true, "()": true, "(())()": true, "(": false, ")(": false, "())": false}, "grammar for balanced parentheses"},
+	{"E -> E + T | T\nT -> T * F | F\nF -> ( E ) | x", map[string]bool{"x": true, "x+x*x": true, "(x+x)*x": true, "": false, "x+": false, "(x": false}, "left recursive grammar for arithmetic expressions"},
+	{"S -> A B C\nA -> a | ε\nB -> b | ε\nC -> c | ε", map[string]bool{"": true, "a": true, "bc": true, "abc": true, "ac": true, "cb": false, "aa": false}, "grammar with nullable variables in one long body"},
+	{"S -> A | x\nA -> B\nB -> S | b", map[string]bool{"x": true, "b": true, "": false, "xb": false}, "grammar with a cycle of unit productions"},
+	{"S -> a | U\nU -> U a\nV -> b", map[string]bool{"a": true, "aa": false, "b": false}, "grammar with non-generating and unreachable variables"},
+}
+
+//brute force membership: expands leftmost variables breadth-first, pruning
+//sentential forms with more terminals than the word. fine for small grammars
+//without ε- or unit-cycles, and used as the reference for the normal forms.
+func derives(g *CFG, w string, maxForms int) bool {
+	queue := [][]string{{g.start}}
+	seen := map[string]bool{}
+	for n := 0; len(queue) > 0 && n < maxForms; n++ {
+		form := queue[0]
+		queue = queue[1:]
+		i := 0
+		for i < len(form) && g.terminals.Contains(form[i]) {
+			i++
+		}
+		prefix := ""
+		terminals := 0
+		for j, symbol := range form {
+			if g.terminals.Contains(symbol) {
+				terminals++
+				if j < i {
+					prefix += symbol
+				}
+			}
+		}
+		if terminals > len(w) || len(prefix) > len(w) || w[:len(prefix)] != prefix {
+			continue
+		}
+		if i == len(form) {
+			if prefix == w {
+				return true
+			}
+			continue
+		}
+		for _, p := range g.productions {
+			if p.Head != form[i] {
+				continue
+			}
+			next := append(append(append([]string(nil), form[:i]...), p.Body...), form[i+1:]...)
+			key := ""
+			for _, symbol := range next {
+				key += symbol + " "
+			}
+			if !seen[key] && len(next) <= 2*len(w)+2 {
+				seen[key] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+func TestCFGNormalForms(t *testing.T) {
+	for _, test := range normalFormTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		cnf, err := g.ToCNF()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		gnf, err := g.ToGNF()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		for _, p := range cnf.productions {
+			ok := len(p.Body) == 0 && p.Head == cnf.start ||
+				len(p.Body) == 1 && cnf.terminals.Contains(p.Body[0]) ||
+				len(p.Body) == 2 && cnf.variables.Contains(p.Body[0]) && cnf.variables.Contains(p.Body[1]) && p.Body[0] != cnf.start && p.Body[1] != cnf.start
+			if !ok {
+				t.Error("On test: " + test.descriptor + ", error: production " + p.String() + " is not in Chomsky normal form")
+			}
+		}
+		for _, p := range gnf.productions {
+			ok := len(p.Body) == 0 && p.Head == gnf.start || len(p.Body) > 0 && gnf.terminals.Contains(p.Body[0])
+			for i, symbol := range p.Body {
+				ok = ok && (i == 0 || gnf.variables.Contains(symbol) && symbol != gnf.start)
+			}
+			if !ok {
+				t.Error("On test: " + test.descriptor + ", error: production " + p.String() + " is not in Greibach normal form")
+			}
+		}
+		for w, v := range test.testWords {
+			if derives(cnf, w, 100000) != v {
+				t.Error("On test: " + test.descriptor + ", error: CNF grammar should have answered " + strconv.FormatBool(v) + " to string " + w)
+			}
+			if derives(gnf, w, 100000) != v {
+				t.Error("On test: " + test.descriptor + ", error: GNF grammar should have answered " + strconv.FormatBool(v) + " to string " + w)
+			}
+		}
+	}
+}
+
+func TestCFGNormalFormSteps(t *testing.T) {
+	g, err := ParseCFG("S -> A B C | S\nA -> a | ε\nB -> b\nC -> C c | ε\nD -> d")
+	if err != nil {
+		t.Error("On test: normal form steps, error: " + err.Error())
+		t.FailNow()
+	}
+	steps := []struct {
+		step     func() (*CFG, error)
+		expected string
+		name     string
+	}{
+		{g.AddStartVariable, "S0 -> S\nS -> A B C | S\nA -> a | ε\nB -> b\nC -> C c | ε\nD -> d", "AddStartVariable"},
+		{g.SeparateTerminals, "S -> A B C | S\nA -> a | ε\nB -> b\nC -> C T_c | ε\nD -> d\nT_c -> c", "SeparateTerminals"},
+		{g.Binarize, "S -> A X | S\nX -> B C\nA -> a | ε\nB -> b\nC -> C c | ε\nD -> d", "Binarize"},
+		{g.RemoveEpsilon, "S -> A B C | B C | A B | B | S\nA -> a\nB -> b\nC -> C c | c\nD -> d", "RemoveEpsilon"},
+		{g.RemoveUnit, "S -> A B C\nA -> a | ε\nB -> b\nC -> C c | ε\nD -> d", "RemoveUnit"},
+		{g.RemoveUseless, "S -> A B C | S\nA -> a | ε\nB -> b\nC -> C c | ε", "RemoveUseless"},
+	}
+	for _, test := range steps {
+		h, err := test.step()
+		if err != nil {
+			t.Error("On test: " + test.name + ", error: " + err.Error())
+			continue
+		}
+		if h.String() != test.expected {
+			t.Error("On test: " + test.name + ", error: grammar printed as\n" + h.String() + "\nexpected\n" + test.expected)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package gocompute
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// A ParseTree is a derivation tree of a context-free grammar. Leaves are terminals, with nil Children. Every other
+// node is a variable together with the symbols of the body of the production applied to it, so a variable node with
+// no children stands for an ε-production.
+type ParseTree struct {
+	Symbol   string
+	Children []*ParseTree
+}
+
+// String renders t in bracketed form, such as S(a S(ε) b).
+func (t *ParseTree) String() string {
+	if t.Children == nil {
+		return t.Symbol
+	}
+	if len(t.Children) == 0 {
+		return t.Symbol + "(ε)"
+	}
+	parts := make([]string, len(t.Children))
+	for i, child := range t.Children {
+		parts[i] = child.String()
+	}
+	return t.Symbol + "(" + strings.Join(parts, " ") + ")"
+}
+
+// Yield returns the string of terminals at the leaves of t, from left to right.
+func (t *ParseTree) Yield() string {
+	if t.Children == nil {
+		return t.Symbol
+	}
+	yield := ""
+	for _, child := range t.Children {
+		yield += child.Yield()
+	}
+	return yield
+}
+
+//converts a derivation with no holes left into a parse tree
+func (g CFG) parseTree(d *derivation) *ParseTree {
+	t := &ParseTree{d.symbol, nil}
+	if g.variables.Contains(d.symbol) {
+		t.Children = []*ParseTree{}
+		for _, child := range d.children {
+			t.Children = append(t.Children, g.parseTree(child))
+		}
+	}
+	return t
+}
+
+// A ParseResult is the result of parsing a string with a CFG. Member reports whether the string is in the language
+// of the grammar. If it is, Tree is one parse tree for it and Trees is the number of distinct parse trees, which is
+// more than one exactly when the string is ambiguous.
+type ParseResult struct {
+	Member bool
+	Tree   *ParseTree
+	Trees  *big.Int
+}
+
+//an entry of the CYK table: how many derivations a variable has over a span, and
+//the rule and split point of the first one found, from which to rebuild a tree
+type cykEntry struct {
+	count *big.Int
+	rule  int
+	split int
+}
+
+// Given a CFG g and a string w, g.Parse(w) decides whether w is in L(g) using the CYK algorithm over g.ToCNF(), and
+// returns a parse tree and the number of distinct parse trees. Both refer to the productions of g, not to the helper
+// variables introduced by the conversion to Chomsky normal form, which remembers the derivation in g that each of its
+// productions stands for.
+//
+// A grammar with ε-productions or cycles of unit productions can have infinitely many parse trees for a string. Parse
+// counts trees that differ only in how they derive ε from a nullable variable once, and ignores trees in which a chain
+// of unit productions repeats a variable. Every symbol of w must be a terminal of g.
+func (g CFG) Parse(w string) (*ParseResult, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	input := []rune(w)
+	for _, r := range input {
+		if !g.terminals.Contains(string(r)) {
+			return nil, errors.New("gocompute/cfg: string to parse not in terminals of CFG")
+		}
+	}
+	c := g.cnfRules()
+	n := len(input)
+	result := &ParseResult{false, nil, big.NewInt(0)}
+
+	if n == 0 {
+		for _, rule := range c.rules {
+			if rule.Head == c.start && len(rule.Body) == 0 {
+				result.Member = true
+				result.Trees.SetInt64(1)
+				result.Tree = g.parseTree(rule.origin[0])
+				break
+			}
+		}
+		return result, nil
+	}
+
+	//table[i][l] holds the entries for the span of length l+1 starting at i
+	table := make([][]map[string]*cykEntry, n)
+	for i := range table {
+		table[i] = make([]map[string]*cykEntry, n-i)
+		for l := range table[i] {
+			table[i][l] = map[string]*cykEntry{}
+		}
+	}
+	record := func(cell map[string]*cykEntry, head string, count *big.Int, rule, split int) {
+		if e, ok := cell[head]; ok {
+			e.count.Add(e.count, count)
+			return
+		}
+		cell[head] = &cykEntry{new(big.Int).Set(count), rule, split}
+	}
+
+	one := big.NewInt(1)
+	for i, r := range input {
+		for k, rule := range c.rules {
+			if len(rule.Body) == 1 && rule.Body[0] == string(r) {
+				record(table[i][0], rule.Head, one, k, 0)
+			}
+		}
+	}
+	for l := 1; l < n; l++ {
+		for i := 0; i+l < n; i++ {
+			for split := 1; split <= l; split++ {
+				left, right := table[i][split-1], table[i+split][l-split]
+				for k, rule := range c.rules {
+					if len(rule.Body) != 2 {
+						continue
+					}
+					b, okB := left[rule.Body[0]]
+					d, okD := right[rule.Body[1]]
+					if okB && okD {
+						record(table[i][l], rule.Head, new(big.Int).Mul(b.count, d.count), k, split)
+					}
+				}
+			}
+		}
+	}
+
+	top, ok := table[0][n-1][c.start]
+	if !ok {
+		return result, nil
+	}
+	//rebuild the first CNF derivation found and map it back through the origins
+	var expand func(head string, i, l int) []*derivation
+	expand = func(head string, i, l int) []*derivation {
+		e := table[i][l][head]
+		rule := c.rules[e.rule]
+		return fillHoles(rule.origin, func(hole *derivation) []*derivation {
+			switch {
+			case len(rule.Body) == 1:
+				return []*derivation{{hole.symbol, nil, -1}}
+			case hole.hole == 0:
+				return expand(rule.Body[0], i, e.split-1)
+			}
+			return expand(rule.Body[1], i+e.split, l-e.split)
+		})
+	}
+	result.Member = true
+	result.Trees = top.count
+	result.Tree = g.parseTree(expand(c.start, 0, n-1)[0])
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package gocompute
+
+import (
+	"strconv"
+	"testing"
+)
+
+var cykTests = []struct {
+	text       string
+	w          string
+	member     bool
+	trees      int64
+	tree       string
+	descriptor string
+}{
+	{"S -> a S b | ε", "aabb", true, 1, "S(a S(a S(ε) b) b)", "grammar for a^n b^n"},
+	{"S -> a S b | ε", "", true, 1, "S(ε)", "grammar for a^n b^n on the empty string"},
+	{"S -> a S b | ε", "abb", false, 0, "", "grammar for a^n b^n on a string not in the language"},
+	{"E -> E + T | T\nT -> T * F | F\nF -> ( E ) | x", "x+x*x", true, 1, "E(E(T(F(x))) + T(T(F(x)) * F(x)))", "unambiguous grammar for arithmetic expressions"},
+	{"E -> E + E | E * E | x", "x+x*x", true, 2, "", "ambiguous grammar for arithmetic expressions"},
+	{"S -> S S | a", "aaaa", true, 5, "", "grammar with a Catalan number of trees"},
+	{"S -> A A\nA -> a | ε", "a", true, 2, "", "grammar where either of two nullable variables can derive the terminal"},
+	{"S -> A | x\nA -> B\nB -> S | b", "b", true, 1, "S(A(B(b)))", "grammar with a cycle of unit productions"},
+	{"S -> A B C\nA -> a | ε\nB -> b\nC -> c | ε", "b", true, 1, "S(A(ε) B(b) C(ε))", "grammar with ε-subtrees on both sides of a long body"},
+}
+
+func TestCFGParse(t *testing.T) {
+	for _, test := range cykTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		result, err := g.Parse(test.w)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if result.Member != test.member {
+			t.Error("On test: " + test.descriptor + ", error: wrong membership for string " + test.w)
+			continue
+		}
+		if result.Trees.Int64() != test.trees {
+			t.Error("On test: " + test.descriptor + ", error: expected " + strconv.FormatInt(test.trees, 10) + " trees, got " + result.Trees.String())
+		}
+		if !test.member {
+			continue
+		}
+		if result.Tree.Yield() != test.w {
+			t.Error("On test: " + test.descriptor + ", error: tree " + result.Tree.String() + " does not yield " + test.w)
+		}
+		if test.tree != "" && result.Tree.String() != test.tree {
+			t.Error("On test: " + test.descriptor + ", error: expected tree " + test.tree + ", got " + result.Tree.String())
+		}
+		if !usesProductionsOf(g, result.Tree) {
+			t.Error("On test: " + test.descriptor + ", error: tree " + result.Tree.String() + " uses productions not in the grammar")
+		}
+	}
+	g, _ := ParseCFG("S -> a")
+	if _, err := g.Parse("b"); err == nil {
+		t.Error("On test: string outside terminals, error: should be an error")
+	}
+}
+
+//checks that every node of a parse tree applies a production of g
+func usesProductionsOf(g *CFG, tree *ParseTree) bool {
+	if tree.Children == nil {
+		return g.terminals.Contains(tree.Symbol)
+	}
+	p := Production{tree.Symbol, nil}
+	for _, child := range tree.Children {
+		p.Body = append(p.Body, child.Symbol)
+		if !usesProductionsOf(g, child) {
+			return false
+		}
+	}
+	for _, q := range g.productions {
+		if q.key() == p.key() {
+			return true
+		}
+	}
+	return false
+}
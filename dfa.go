@@ -8,6 +8,7 @@ import (
 	"errors"
 	"github.com/jophish/golang-set"
 	"reflect"
+	"sort"
 )
 
 // Internal representation of a DFA
@@ -119,9 +120,6 @@ func (d1 DFA) Intersection(d2 *DFA) (*DFA, error) {
 // Given a DFA d1 with alphabet E, recognizing language L(d1), d1.Complement() returns a pointer to a new DFA recognizing
 // the set E* - L(d1), where E* is the set of all strings that can be created from symbols in the alphabet E.
 func (d1 DFA) Complement() (*DFA, error) {
-	if !d1.alphabet.Equal(d2.alphabet) {
-		return nil, errors.New("gocompute/dfa: alphabets of input DFAs must be equal")
-	}
 	ans, err := d1.CheckDFA()
 	if ans == false && err != nil {
 		return nil, errors.New("gocompute/dfa: invalid DFA: " + err.Error())
@@ -224,6 +222,37 @@ func simDFA(w string, d *DFA) (bool, error) {
 	}
 	return false, nil
 }
+
+//returns the symbols of an alphabet as a sorted slice, so that algorithms which walk the
+//alphabet produce the same output on every run
+func sortedAlphabet(alphabet mapset.Set) []string {
+	symbols := make([]string, 0, alphabet.Cardinality())
+	for _, elem := range alphabet.ToSlice() {
+		symbols = append(symbols, elem.(string))
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+//returns the states reachable from the start state of d in breadth-first order,
+//along with the shortest (then lexicographically least) word reaching each one
+func reachableStates(d *DFA) ([]interface{}, map[interface{}]string) {
+	symbols := sortedAlphabet(d.alphabet)
+	order := []interface{}{d.start}
+	access := map[interface{}]string{d.start: ""}
+	for i := 0; i < len(order); i++ {
+		state := order[i]
+		for _, a := range symbols {
+			next := d.transition(state, a)
+			if _, seen := access[next]; !seen {
+				access[next] = access[state] + a
+				order = append(order, next)
+			}
+		}
+	}
+	return order, access
+}
+
 // Checks to make sure a given DFA d is properly formatted with correct input data.
 func (d DFA) CheckDFA() (bool, error) {
 	//check that all elements of states are of same type (interface{})
@@ -0,0 +1,351 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"strings"
+)
+
+// Internal representation of a DPDA
+type DPDA struct {
+	states        mapset.Set
+	alphabet      mapset.Set
+	stackAlphabet mapset.Set
+	transition    func(state, input, stackSymbol string) (moves mapset.Set)
+	start         string
+	stackStart    string
+	accept        mapset.Set
+}
+
+// Constructor method for creating a DPDA, a deterministic PDA accepting by final state. The arguments are as for
+// NewPDA. Returns a pointer to the newly created DPDA and an error, which is non-nil if the input was improperly
+// formatted or the transition function is not deterministic.
+//
+// A DPDA is deterministic when, in every configuration, at most one move applies. A move applies to a configuration
+// if it reads the next input symbol or nothing, and pops the top stack symbol or leaves the stack alone, so in
+// particular a state with an ε-move for some stack symbol may not have any other move for that symbol.
+func NewDPDA(states,
+	alphabet,
+	stackAlphabet mapset.Set,
+	transition func(state, input, stackSymbol string) (moves mapset.Set),
+	start,
+	stackStart string,
+	accept mapset.Set) (*DPDA, error) {
+
+	p := &DPDA{states, alphabet, stackAlphabet, transition, start, stackStart, accept}
+	ans, err := p.CheckDPDA()
+	if ans != true && err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ToPDA returns p as a general PDA accepting by final state.
+func (p DPDA) ToPDA() (*PDA, error) {
+	return NewPDA(p.states, p.alphabet, p.stackAlphabet, p.transition, p.start, p.stackStart, p.accept)
+}
+
+//returns the number of moves of p from state on input and stack symbol
+func (p DPDA) countMoves(state, input, stackSymbol string) int {
+	if moves := p.transition(state, input, stackSymbol); moves != nil {
+		return moves.Cardinality()
+	}
+	return 0
+}
+
+// Checks to make sure a given DPDA p is properly formatted with correct input data, and deterministic.
+func (p DPDA) CheckDPDA() (bool, error) {
+	general := PDA{p.states, p.alphabet, p.stackAlphabet, p.transition, p.start, p.stackStart, p.accept, AcceptFinalState}
+	ans, err := general.CheckPDA()
+	if ans != true && err != nil {
+		return false, errors.New("gocompute/dpda: " + strings.TrimPrefix(err.Error(), "gocompute/pda: "))
+	}
+
+	//every combination of next input symbol, or none left, and top stack symbol, or
+	//an empty stack, must allow at most one move
+	inputs := append([]string{""}, sortedAlphabet(p.alphabet)...)
+	tops := append([]string{""}, sortedAlphabet(p.stackAlphabet)...)
+	for _, state := range sortedAlphabet(p.states) {
+		for _, a := range inputs {
+			for _, top := range tops {
+				n := p.countMoves(state, "", "")
+				if top != "" {
+					n += p.countMoves(state, "", top)
+				}
+				if a != "" {
+					n += p.countMoves(state, a, "")
+					if top != "" {
+						n += p.countMoves(state, a, top)
+					}
+				}
+				if n > 1 {
+					input, stack := "end of input", "empty stack"
+					if a != "" {
+						input = "input " + a
+					}
+					if top != "" {
+						stack = "stack top " + top
+					}
+					return false, errors.New("gocompute/dpda: state " + state + " has more than one move on " + input + " with " + stack)
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+//returns the move of p from state on input, which is "" for an ε-move, with the
+//given stack, top last. the move is returned as the new state and the stack
+//symbols to push in place of the top, top last, with popped set if the top is replaced.
+func (p DPDA) move(state, input string, stack []string) (next string, push []string, popped bool, ok bool) {
+	choose := func(moves mapset.Set) (string, []string, bool) {
+		if moves == nil || moves.Cardinality() == 0 {
+			return "", nil, false
+		}
+		//p is deterministic, so there is just the one move
+		m := moves.ToSlice()[0].(PDAMove)
+		r := []rune(m.Push)
+		push = make([]string, len(r))
+		for i, c := range r {
+			push[len(r)-1-i] = string(c)
+		}
+		return m.State, push, true
+	}
+	if len(stack) > 0 {
+		if next, push, ok = choose(p.transition(state, input, stack[len(stack)-1])); ok {
+			return next, push, true, true
+		}
+	}
+	next, push, ok = choose(p.transition(state, input, ""))
+	return next, push, false, ok
+}
+
+//follows the ε-moves of p from state with the given stack, top last, until none
+//applies, the stack drops below floor symbols, or p is found to loop forever. visit
+//is called on every state entered. a loop is detected when p returns to the same
+//state and top symbol without the stack having dropped below the height of the
+//earlier visit, since from then on it must repeat itself.
+func (p DPDA) epsilonRun(state string, stack []string, floor int, visit func(state string)) (string, []string, bool) {
+	type key struct {
+		state, top string
+	}
+	//the height of the valid visit of each state and top, and the visits at each height
+	visits := map[key]int{}
+	byHeight := map[int][]key{}
+	for {
+		if len(stack) < floor {
+			return state, stack, false
+		}
+		k := key{state, ""}
+		if len(stack) > 0 {
+			k.top = stack[len(stack)-1]
+		}
+		if h, ok := visits[k]; ok && h <= len(stack) {
+			return state, stack, true
+		}
+		next, push, popped, ok := p.move(state, "", stack)
+		if !ok {
+			return state, stack, false
+		}
+		visits[k] = len(stack)
+		byHeight[len(stack)] = append(byHeight[len(stack)], k)
+		if popped {
+			stack = stack[:len(stack)-1]
+			//visits above the new height can never be repeated without change
+			for _, old := range byHeight[len(stack)+1] {
+				delete(visits, old)
+			}
+			delete(byHeight, len(stack)+1)
+		}
+		stack = append(stack, push...)
+		state = next
+		visit(state)
+	}
+}
+
+// Given a DPDA p and a string w, p.Simulate(w) runs p on w and returns true if p accepts w, that is, if p reads all
+// of w and is in an accept state at some point afterwards. Since p is deterministic there is a single computation to
+// follow, so simulation takes time linear in the length of w and the number of moves p makes. A DPDA which loops
+// forever on ε-moves is detected, and accepts only if the loop starts after all of w is read and passes through an
+// accept state.
+func (p DPDA) Simulate(w string) (bool, error) {
+	ans, err := p.CheckDPDA()
+	if ans != true && err != nil {
+		return false, errors.New("gocompute/dpda: invalid DPDA: " + err.Error())
+	}
+	input := []rune(w)
+	for _, r := range input {
+		if !p.alphabet.Contains(string(r)) {
+			return false, errors.New("gocompute/dpda: string to test not in alphabet of DPDA")
+		}
+	}
+
+	state, stack := p.start, []string{p.stackStart}
+	for pos := 0; ; pos++ {
+		accepted := pos == len(input) && p.accept.Contains(state)
+		state, stack, _ = p.epsilonRun(state, stack, 0, func(s string) {
+			accepted = accepted || (pos == len(input) && p.accept.Contains(s))
+		})
+		if pos == len(input) {
+			return accepted, nil
+		}
+		next, push, popped, ok := p.move(state, string(input[pos]), stack)
+		if !ok {
+			return false, nil
+		}
+		if popped {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, push...)
+		state = next
+	}
+}
+
+// Given a DPDA p, p.Complement() returns a pointer to a new DPDA recognizing the strings over the alphabet of p
+// which p rejects. The usual obstacles are dealt with as follows. A fresh bottom-of-stack marker means the new DPDA
+// never empties its stack, and whenever p has no move, a dead state reads the rest of the input. Configurations from
+// which p loops forever on ε-moves are found in advance and replaced by moves to the dead state, or to a rejecting
+// state if p would have accepted there. Finally, since p accepts if it passes through an accept state anywhere in
+// the ε-moves after its last input symbol, each state of p is split in three: one remembering that an accept state
+// was seen since the last input symbol, one that it was not, and an accepting copy entered by an ε-move from the
+// latter just before the next input symbol is read.
+func (p DPDA) Complement() (*DPDA, error) {
+	ans, err := p.CheckDPDA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/dpda: invalid DPDA: " + err.Error())
+	}
+	encode := func(state, flag string) string {
+		return state + "/" + flag
+	}
+	states := mapset.NewSet()
+	for _, q := range sortedAlphabet(p.states) {
+		states.Add(encode(q, "0"))
+		states.Add(encode(q, "1"))
+		states.Add(encode(q, "r"))
+	}
+	start := freshState(states, "start")
+	states.Add(start)
+	dead := freshState(states, "dead")
+	states.Add(dead)
+	halted := freshState(states, "halted")
+	states.Add(halted)
+	bottom := freshStackSymbol(p.stackAlphabet)
+	decode := map[string][2]string{}
+	for _, q := range sortedAlphabet(p.states) {
+		for _, flag := range []string{"0", "1", "r"} {
+			decode[encode(q, flag)] = [2]string{q, flag}
+		}
+	}
+	flagOf := func(seen bool, q string) string {
+		if seen || p.accept.Contains(q) {
+			return "1"
+		}
+		return "0"
+	}
+
+	//the move of p from q on input with top symbol top, as a move of the new DPDA
+	//which always pops the top, or false if there is none
+	translate := func(q, input, top string) (PDAMove, bool) {
+		stack := []string{}
+		if top != bottom {
+			stack = append(stack, top)
+		}
+		next, push, popped, ok := p.move(q, input, stack)
+		if !ok {
+			return PDAMove{}, false
+		}
+		s := ""
+		for i := len(push) - 1; i >= 0; i-- {
+			s += push[i]
+		}
+		if !popped {
+			s += top
+		}
+		return PDAMove{next, s}, true
+	}
+	//whether p loops forever on ε-moves from q with top on its stack, and whether it
+	//passes through an accept state while doing so
+	type loop struct {
+		forever, accepts bool
+	}
+	loops := map[[2]string]loop{}
+	loopsFrom := func(q, top string) loop {
+		if l, ok := loops[[2]string{q, top}]; ok {
+			return l
+		}
+		stack, floor := []string{}, 0
+		if top != bottom {
+			stack, floor = []string{top}, 1
+		}
+		l := loop{}
+		_, _, l.forever = p.epsilonRun(q, stack, floor, func(s string) {
+			l.accepts = l.accepts || p.accept.Contains(s)
+		})
+		loops[[2]string{q, top}] = l
+		return l
+	}
+
+	transition := func(state, input, stackSymbol string) (moves mapset.Set) {
+		moves = mapset.NewSet()
+		if stackSymbol == "" {
+			return moves
+		}
+		switch state {
+		case start:
+			if input == "" && stackSymbol == bottom {
+				moves.Add(PDAMove{encode(p.start, flagOf(false, p.start)), p.stackStart + bottom})
+			}
+			return moves
+		case dead, halted:
+			if input != "" {
+				moves.Add(PDAMove{dead, stackSymbol})
+			}
+			return moves
+		}
+		q, flag := decode[state][0], decode[state][1]
+		//reads an input symbol, moving to the dead state if p cannot
+		read := func() {
+			if m, ok := translate(q, input, stackSymbol); ok {
+				moves.Add(PDAMove{encode(m.State, flagOf(false, m.State)), m.Push})
+			} else {
+				moves.Add(PDAMove{dead, stackSymbol})
+			}
+		}
+		if flag == "r" {
+			if input != "" {
+				read()
+			}
+			return moves
+		}
+		if m, ok := translate(q, "", stackSymbol); ok {
+			if input != "" {
+				return moves
+			}
+			if l := loopsFrom(q, stackSymbol); l.forever {
+				if flag == "1" || l.accepts {
+					moves.Add(PDAMove{halted, stackSymbol})
+				} else {
+					moves.Add(PDAMove{dead, stackSymbol})
+				}
+				return moves
+			}
+			moves.Add(PDAMove{encode(m.State, flagOf(flag == "1", m.State)), m.Push})
+			return moves
+		}
+		//p would read next
+		switch {
+		case flag == "0" && input == "":
+			moves.Add(PDAMove{encode(q, "r"), stackSymbol})
+		case flag == "1" && input != "":
+			read()
+		}
+		return moves
+	}
+
+	accept := mapset.NewSet(dead)
+	for _, q := range sortedAlphabet(p.states) {
+		accept.Add(encode(q, "r"))
+	}
+	stackAlphabet := p.stackAlphabet.Union(mapset.NewSet(bottom))
+	return NewDPDA(states, p.alphabet, stackAlphabet, transition, start, bottom, accept)
+}
@@ -0,0 +1,173 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+)
+
+func makeAnBnDPDA() (*DPDA, error) {
+	//qs accepts the empty string, q0 reads a's pushing A, q1 reads b's popping A, and
+	//q2 accepts once the bottom marker Z is back on top
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "qs" && input == "a" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q0", "AZ"})
+		case state == "q0" && input == "a" && stackSymbol == "A":
+			return pdaMoves(PDAMove{"q0", "AA"})
+		case state == "q0" && input == "b" && stackSymbol == "A":
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q1" && input == "b" && stackSymbol == "A":
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q1" && input == "" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q2", "Z"})
+		}
+		return nil
+	}
+	return NewDPDA(mapset.NewSet("qs", "q0", "q1", "q2"), mapset.NewSet("a", "b"), mapset.NewSet("Z", "A"), transition, "qs", "Z", mapset.NewSet("qs", "q2"))
+}
+
+func makeBalancedDPDA() (*DPDA, error) {
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "q0" && input == "(" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q1", "(Z"})
+		case state == "q1" && input == "(" && stackSymbol == "(":
+			return pdaMoves(PDAMove{"q1", "(("})
+		case state == "q1" && input == ")" && stackSymbol == "(":
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q1" && input == "" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q0", "Z"})
+		}
+		return nil
+	}
+	return NewDPDA(mapset.NewSet("q0", "q1"), mapset.NewSet("(", ")"), mapset.NewSet("Z", "("), transition, "q0", "Z", mapset.NewSet("q0"))
+}
+
+func makeEpsilonLoopDPDA() (*DPDA, error) {
+	//after an a, q1 and q2 swap forever through the accept state q2, and after a b,
+	//q3 pushes X forever
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "q0" && input == "a" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q1", "Z"})
+		case state == "q0" && input == "b" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q3", "Z"})
+		case state == "q1" && input == "" && stackSymbol == "":
+			return pdaMoves(PDAMove{"q2", ""})
+		case state == "q2" && input == "" && stackSymbol == "":
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q3" && input == "" && stackSymbol == "":
+			return pdaMoves(PDAMove{"q3", "X"})
+		}
+		return nil
+	}
+	return NewDPDA(mapset.NewSet("q0", "q1", "q2", "q3"), mapset.NewSet("a", "b"), mapset.NewSet("Z", "X"), transition, "q0", "Z", mapset.NewSet("q2"))
+}
+
+var dp1, dp1err = makeAnBnDPDA()
+var dp2, dp2err = makeBalancedDPDA()
+var dp3, dp3err = makeEpsilonLoopDPDA()
+
+var dpdaSimulateTests = []struct {
+	p           *DPDA
+	err         error
+	alphabet    []string
+	testStrings map[string]bool
+	descriptor  string
+}{
+	{dp1, dp1err, []string{"a", "b"}, map[string]bool{"": true, "ab": true, "aabb": true, "aaabbb": true, "a": false, "abb": false, "aab": false, "ba": false, "abab": false}, "DPDA accepting a^n b^n"},
+	{dp2, dp2err, []string{"(", ")"}, map[string]bool{"": true, "()": true, "(())()": true, "(()(()))": true, "(": false, ")": false, "())(": false, "(()": false}, "DPDA accepting balanced parentheses"},
+	{dp3, dp3err, []string{"a", "b"}, map[string]bool{"": false, "a": true, "aa": false, "ab": false, "b": false, "ba": false}, "DPDA looping forever on ε-moves"},
+}
+
+func TestDPDASimulate(t *testing.T) {
+	for _, test := range dpdaSimulateTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		for k, v := range test.testStrings {
+			ans, err := test.p.Simulate(k)
+			if ans != v {
+				t.Error("On test: " + test.descriptor + ", error: DPDA should have answered " + strconv.FormatBool(v) + " to string " + k)
+			}
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", while testing string " + k + ", error: " + err.Error())
+			}
+		}
+	}
+}
+
+func TestDPDAAgreesWithPDA(t *testing.T) {
+	for _, test := range dpdaSimulateTests[:2] {
+		p, err := test.p.ToPDA()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		for _, w := range allStrings(test.alphabet, 6) {
+			want, _ := p.Simulate(w)
+			if ans, _ := test.p.Simulate(w); ans != want {
+				t.Error("On test: " + test.descriptor + ", error: DPDA and PDA disagree on string " + w)
+			}
+		}
+	}
+}
+
+func TestDPDAComplement(t *testing.T) {
+	for _, test := range dpdaSimulateTests {
+		c, err := test.p.Complement()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		for _, w := range allStrings(test.alphabet, 6) {
+			ans, _ := test.p.Simulate(w)
+			if comp, err := c.Simulate(w); comp == ans || err != nil {
+				t.Error("On test: complement of " + test.descriptor + ", error: should have answered " + strconv.FormatBool(!ans) + " to string " + w)
+			}
+		}
+	}
+}
+
+func TestCheckDPDA(t *testing.T) {
+	p2, _ := makeEvenPalindromePDA()
+	epsilonConflict := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case input == "" && stackSymbol == "":
+			return pdaMoves(PDAMove{"q0", ""})
+		case input == "a" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q0", "Z"})
+		}
+		return nil
+	}
+	popConflict := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case input == "a" && stackSymbol == "":
+			return pdaMoves(PDAMove{"q0", "Z"})
+		case input == "a" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q0", ""})
+		}
+		return nil
+	}
+	var tests = []struct {
+		transition func(state, input, stackSymbol string) mapset.Set
+		valid      bool
+		descriptor string
+	}{
+		{p2.transition, false, "guessing the middle of a palindrome"},
+		{epsilonConflict, false, "ε-move leaving the stack alone alongside a move reading input"},
+		{popConflict, false, "move leaving the stack alone alongside a move popping on the same input"},
+		{dp1.transition, true, "deterministic moves"},
+	}
+	for _, test := range tests {
+		_, err := NewDPDA(mapset.NewSet("q0", "q1", "q2", "qs"), mapset.NewSet("a", "b"), mapset.NewSet("Z", "A", "a", "b"), test.transition, "q0", "Z", mapset.NewSet("q2"))
+		if test.valid && err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+		}
+		if !test.valid && err == nil {
+			t.Error("On test: " + test.descriptor + ", error: nondeterminism should have been reported")
+		}
+	}
+}
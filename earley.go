@@ -0,0 +1,366 @@
+package gocompute
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A ParseError describes why a string is not in the language of a grammar. Position is the number of input symbols
+// the parser could read before no production could continue, and Expected lists, in sorted order, the terminals
+// which could have come next there. If the whole input was read, Position is its length, and Expected lists
+// terminals which could extend it into a string of the language.
+type ParseError struct {
+	Position int
+	Found    string
+	Expected []string
+}
+
+// Error describes the furthest position reached and what was expected there.
+func (e *ParseError) Error() string {
+	found := "end of input"
+	if e.Found != "" {
+		found = "\"" + e.Found + "\""
+	}
+	expected := "end of input"
+	if len(e.Expected) > 0 {
+		expected = "one of " + strings.Join(e.Expected, ", ")
+	}
+	return "gocompute/cfg: at position " + strconv.Itoa(e.Position) + ", found " + found + ", expected " + expected
+}
+
+// An SPPF is a shared packed parse forest: a compact representation of every parse tree of a string. Each node
+// stands for one symbol, or a prefix of a production body, deriving one span of the input, and is shared by every
+// tree using it. Families are binarized as in Scott's construction, so the forest has size at most cubic in the
+// length of the string.
+type SPPF struct {
+	Root *SPPFNode
+}
+
+// An SPPFNode is a symbol deriving the input between positions Start and End. A variable node has one family for each
+// distinct way it derives the span; a node with more than one family marks an ambiguity. Terminal nodes have no
+// families.
+//
+// An intermediate node has an empty Symbol and stands for all but the last symbol of the body of its families'
+// production, with at least two symbols, deriving the span. Its families split the span between those symbols.
+type SPPFNode struct {
+	Symbol       string
+	Start, End   int
+	Intermediate bool
+	Families     []SPPFFamily
+}
+
+// An SPPFFamily is one way of deriving a node: a production applied to it, and the nodes its span is split into. The
+// symbols derived are the body of the production, or for an intermediate node the prefix of it the node stands for.
+// With no symbols there are no children, and with one there is a node for it. Otherwise there are two children
+// covering consecutive spans: one for all the symbols but the last, which is intermediate if there are at least two
+// of them, and one for the last symbol.
+type SPPFFamily struct {
+	Production Production
+	Children   []*SPPFNode
+}
+
+//an Earley item: a production, how much of its body has been recognized, and
+//the position at which recognizing it began
+type earleyItem struct {
+	production int
+	dot        int
+	origin     int
+}
+
+//a span of the input derived by a symbol
+type earleySpan struct {
+	symbol     string
+	start, end int
+}
+
+type earleyChart struct {
+	g     *CFG
+	input []string
+	sets  [][]earleyItem
+	spans map[earleySpan]bool
+	reach int
+}
+
+//runs the Earley recognizer. nullable variables are handled as suggested by Aycock
+//and Horspool: predicting a nullable variable also moves the dot past it.
+func (g CFG) earley(w string) *earleyChart {
+	input := make([]string, 0, len(w))
+	for _, r := range w {
+		input = append(input, string(r))
+	}
+	c := &earleyChart{&g, input, make([][]earleyItem, len(input)+1), map[earleySpan]bool{}, 0}
+	byHead := g.productionsByHead()
+	nullable := g.rules().nullable()
+
+	for j := 0; j <= len(input); j++ {
+		seen := map[earleyItem]bool{}
+		add := func(item earleyItem) {
+			if !seen[item] {
+				seen[item] = true
+				c.sets[j] = append(c.sets[j], item)
+			}
+		}
+		if j == 0 {
+			for _, p := range byHead[g.start] {
+				add(earleyItem{p, 0, 0})
+			}
+		} else {
+			//scan
+			for _, item := range c.sets[j-1] {
+				body := g.productions[item.production].Body
+				//a variable may share its name with an input symbol, so only terminals are scanned
+				if item.dot < len(body) && body[item.dot] == input[j-1] && g.terminals.Contains(input[j-1]) {
+					add(earleyItem{item.production, item.dot + 1, item.origin})
+				}
+			}
+		}
+		if len(c.sets[j]) == 0 {
+			break
+		}
+		c.reach = j
+
+		for k := 0; k < len(c.sets[j]); k++ {
+			item := c.sets[j][k]
+			p := g.productions[item.production]
+			if item.dot == len(p.Body) {
+				//complete
+				c.spans[earleySpan{p.Head, item.origin, j}] = true
+				for _, parent := range c.sets[item.origin] {
+					body := g.productions[parent.production].Body
+					if parent.dot < len(body) && body[parent.dot] == p.Head {
+						add(earleyItem{parent.production, parent.dot + 1, parent.origin})
+					}
+				}
+				continue
+			}
+			next := p.Body[item.dot]
+			if !g.variables.Contains(next) {
+				continue
+			}
+			//predict
+			for _, q := range byHead[next] {
+				add(earleyItem{q, 0, j})
+			}
+			if _, ok := nullable[next]; ok {
+				add(earleyItem{item.production, item.dot + 1, item.origin})
+			}
+		}
+	}
+	return c
+}
+
+func (c *earleyChart) accepts() bool {
+	return c.spans[earleySpan{c.g.start, 0, len(c.input)}]
+}
+
+func (c *earleyChart) parseError() *ParseError {
+	e := &ParseError{Position: c.reach}
+	if c.reach < len(c.input) {
+		e.Found = c.input[c.reach]
+	}
+	expected := map[string]bool{}
+	for _, item := range c.sets[c.reach] {
+		body := c.g.productions[item.production].Body
+		if item.dot < len(body) && c.g.terminals.Contains(body[item.dot]) {
+			expected[body[item.dot]] = true
+		}
+	}
+	for t := range expected {
+		e.Expected = append(e.Expected, t)
+	}
+	sort.Strings(e.Expected)
+	return e
+}
+
+// Given a CFG g and a string w, g.Recognize(w) decides whether w is in L(g) using Earley's algorithm. Unlike g.Parse,
+// it works on g directly, so it needs no conversion to Chomsky normal form and takes time at most cubic in the length
+// of w, quadratic for unambiguous grammars. Every symbol of w must be a terminal of g.
+func (g CFG) Recognize(w string) (bool, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return false, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	for _, r := range w {
+		if !g.terminals.Contains(string(r)) {
+			return false, errors.New("gocompute/cfg: string to recognize not in terminals of CFG")
+		}
+	}
+	return g.earley(w).accepts(), nil
+}
+
+// Given a CFG g and a string w, g.ParseForest(w) parses w with Earley's algorithm and returns a shared packed parse
+// forest of all its parse trees. Any grammar is allowed, including ambiguous, left recursive and cyclic ones; the
+// forest of a cyclic grammar contains cycles. The forest is read off the items of the Earley sets, and takes time at
+// most cubic in the length of w to build. If w is not in L(g), the error is a *ParseError giving the furthest
+// position reached and the terminals expected there.
+func (g CFG) ParseForest(w string) (*SPPF, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	c := g.earley(w)
+	if !c.accepts() {
+		return nil, c.parseError()
+	}
+
+	//the items of each Earley set, for looking up where a prefix of a body ended
+	items := make([]map[earleyItem]bool, len(c.sets))
+	for j, set := range c.sets {
+		items[j] = map[earleyItem]bool{}
+		for _, item := range set {
+			items[j][item] = true
+		}
+	}
+	byHead := g.productionsByHead()
+	derives := func(symbol string, start, end int) bool {
+		if g.terminals.Contains(symbol) {
+			return end == start+1 && c.input[start] == symbol
+		}
+		return c.spans[earleySpan{symbol, start, end}]
+	}
+
+	//an intermediate node: the first dot symbols of a production deriving a span
+	type prefixSpan struct {
+		production, dot, start, end int
+	}
+	nodes := map[earleySpan]*SPPFNode{}
+	intermediates := map[prefixSpan]*SPPFNode{}
+	var node func(symbol string, start, end int) *SPPFNode
+	var prefix func(production, dot, start, end int) *SPPFNode
+	//returns the families of body[:dot] deriving input[start:end], one for each
+	//position mid at which body[:dot-1] ends and the symbol body[dot-1] begins
+	families := func(production, dot, start, end int) []SPPFFamily {
+		p := g.productions[production]
+		if dot == 0 {
+			return []SPPFFamily{{p, []*SPPFNode{}}}
+		}
+		var all []SPPFFamily
+		for mid := start; mid <= end; mid++ {
+			if !items[mid][earleyItem{production, dot - 1, start}] || !derives(p.Body[dot-1], mid, end) {
+				continue
+			}
+			children := []*SPPFNode{}
+			if dot > 1 {
+				children = append(children, prefix(production, dot-1, start, mid))
+			}
+			all = append(all, SPPFFamily{p, append(children, node(p.Body[dot-1], mid, end))})
+		}
+		return all
+	}
+	node = func(symbol string, start, end int) *SPPFNode {
+		s := earleySpan{symbol, start, end}
+		if n, ok := nodes[s]; ok {
+			return n
+		}
+		//register the node before building its families, so cycles refer back to it
+		n := &SPPFNode{symbol, start, end, false, nil}
+		nodes[s] = n
+		if g.terminals.Contains(symbol) {
+			return n
+		}
+		for _, i := range byHead[symbol] {
+			dot := len(g.productions[i].Body)
+			if items[end][earleyItem{i, dot, start}] {
+				n.Families = append(n.Families, families(i, dot, start, end)...)
+			}
+		}
+		return n
+	}
+	prefix = func(production, dot, start, end int) *SPPFNode {
+		if dot == 1 {
+			return node(g.productions[production].Body[0], start, end)
+		}
+		s := prefixSpan{production, dot, start, end}
+		if n, ok := intermediates[s]; ok {
+			return n
+		}
+		n := &SPPFNode{"", start, end, true, nil}
+		intermediates[s] = n
+		n.Families = families(production, dot, start, end)
+		return n
+	}
+	return &SPPF{node(g.start, 0, len(c.input))}, nil
+}
+
+// IsAmbiguous reports whether f contains more than one parse tree. A forest with cycles is always ambiguous.
+func (f *SPPF) IsAmbiguous() bool {
+	seen := map[*SPPFNode]bool{}
+	var visit func(n *SPPFNode) bool
+	visit = func(n *SPPFNode) bool {
+		if seen[n] {
+			return false
+		}
+		seen[n] = true
+		if len(n.Families) > 1 {
+			return true
+		}
+		for _, family := range n.Families {
+			for _, child := range family.Children {
+				if visit(child) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return visit(f.Root)
+}
+
+// Derivations calls yield with each parse tree in f, in turn, until yield returns false or the trees run out. A
+// forest with cycles has infinitely many trees; only those in which no node is its own descendant are produced.
+func (f *SPPF) Derivations(yield func(tree *ParseTree) bool) {
+	f.derivations(1, yield)
+}
+
+//like Derivations, but lets each node appear up to repeat times on any path from
+//the root, so that cycles are unrolled repeat-1 times
+func (f *SPPF) derivations(repeat int, yield func(tree *ParseTree) bool) {
+	onPath := map[*SPPFNode]int{}
+	//each function passes every tree it can build to k, stopping early if k returns false
+	var trees func(n *SPPFNode, k func(t *ParseTree) bool) bool
+	var sequences func(children []*SPPFNode, built []*ParseTree, k func(ts []*ParseTree) bool) bool
+	trees = func(n *SPPFNode, k func(t *ParseTree) bool) bool {
+		if n.Families == nil {
+			return k(&ParseTree{n.Symbol, nil})
+		}
+		if onPath[n] >= repeat {
+			return true
+		}
+		onPath[n]++
+		defer func() { onPath[n]-- }()
+		for _, family := range n.Families {
+			more := sequences(family.Children, []*ParseTree{}, func(ts []*ParseTree) bool {
+				//n is finished once its tree is passed on, and may appear again in later siblings
+				onPath[n]--
+				more := k(&ParseTree{n.Symbol, ts})
+				onPath[n]++
+				return more
+			})
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+	sequences = func(children []*SPPFNode, built []*ParseTree, k func(ts []*ParseTree) bool) bool {
+		if len(children) == 0 {
+			return k(append([]*ParseTree{}, built...))
+		}
+		if children[0].Intermediate {
+			//an intermediate node stands for several children, so splice in each of its families
+			for _, family := range children[0].Families {
+				rest := append(append([]*SPPFNode{}, family.Children...), children[1:]...)
+				if !sequences(rest, built, k) {
+					return false
+				}
+			}
+			return true
+		}
+		return trees(children[0], func(t *ParseTree) bool {
+			return sequences(children[1:], append(built, t), k)
+		})
+	}
+	trees(f.Root, yield)
+}
@@ -0,0 +1,151 @@
+package gocompute
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var earleyTests = []struct {
+	text       string
+	w          string
+	trees      []string
+	ambiguous  bool
+	descriptor string
+}{
+	{"S -> a S b | ε", "aabb", []string{"S(a S(a S(ε) b) b)"}, false, "grammar for a^n b^n"},
+	{"E -> E + T | T\nT -> T * F | F\nF -> ( E ) | x", "x+x*x", []string{"E(E(T(F(x))) + T(T(F(x)) * F(x)))"}, false, "left recursive grammar for arithmetic expressions"},
+	{"E -> E + E | x", "x+x+x", []string{"E(E(E(x) + E(x)) + E(x))", "E(E(x) + E(E(x) + E(x)))"}, true, "ambiguous grammar for sums"},
+	{"S -> A A\nA -> a | ε", "a", []string{"S(A(a) A(ε))", "S(A(ε) A(a))"}, true, "grammar where either of two nullable variables can derive the terminal"},
+	{"S -> A A\nA -> a | ε", "", []string{"S(A(ε) A(ε))"}, false, "grammar with nullable variables on the empty string"},
+	{"S -> S | a", "a", []string{"S(a)"}, true, "cyclic grammar"},
+	{"S -> A B\nA -> B | a\nB -> A | b", "ab", []string{"S(A(a) B(b))"}, true, "grammar with a unit cycle through two variables"},
+}
+
+func TestCFGParseForest(t *testing.T) {
+	for _, test := range earleyTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		if ans, err := g.Recognize(test.w); !ans || err != nil {
+			t.Error("On test: " + test.descriptor + ", error: string " + test.w + " should be recognized")
+		}
+		f, err := g.ParseForest(test.w)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		var trees []string
+		f.Derivations(func(tree *ParseTree) bool {
+			trees = append(trees, tree.String())
+			return true
+		})
+		sort.Strings(trees)
+		if strings.Join(trees, "\n") != strings.Join(test.trees, "\n") {
+			t.Error("On test: " + test.descriptor + ", error: expected trees\n" + strings.Join(test.trees, "\n") + "\ngot\n" + strings.Join(trees, "\n"))
+		}
+		if f.IsAmbiguous() != test.ambiguous {
+			t.Error("On test: " + test.descriptor + ", error: ambiguity should be " + strconv.FormatBool(test.ambiguous))
+		}
+	}
+}
+
+func TestCFGDerivationsStopEarly(t *testing.T) {
+	g, _ := ParseCFG("S -> S S | a")
+	f, err := g.ParseForest("aaaaaaaa")
+	if err != nil {
+		t.Error("On test: stopping early, error: " + err.Error())
+		t.FailNow()
+	}
+	n := 0
+	f.Derivations(func(tree *ParseTree) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t.Error("On test: stopping early, error: expected 3 trees before stopping, got " + strconv.Itoa(n))
+	}
+}
+
+var earleyErrorTests = []struct {
+	text       string
+	w          string
+	position   int
+	found      string
+	expected   []string
+	descriptor string
+}{
+	{"E -> E + T | T\nT -> x | ( E )", "x+", 2, "", []string{"(", "x"}, "input ending too early"},
+	{"E -> E + T | T\nT -> x | ( E )", "x+x)", 3, ")", []string{"+"}, "unexpected closing parenthesis"},
+	{"E -> E + T | T\nT -> x | ( E )", "xy", 1, "y", []string{"+"}, "symbol which is not a terminal"},
+	{"S -> b A\nA -> c", "bA", 1, "A", []string{"c"}, "symbol which is the name of a variable"},
+	{"S -> a S b | ε", "aab", 3, "", []string{"b"}, "missing b at end of input"},
+}
+
+func TestCFGParseForestErrors(t *testing.T) {
+	for _, test := range earleyErrorTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		_, err = g.ParseForest(test.w)
+		e, ok := err.(*ParseError)
+		if !ok {
+			t.Error("On test: " + test.descriptor + ", error: expected a *ParseError")
+			continue
+		}
+		if e.Position != test.position || e.Found != test.found || strings.Join(e.Expected, " ") != strings.Join(test.expected, " ") {
+			t.Error("On test: " + test.descriptor + ", error: unexpected parse error: " + e.Error())
+		}
+	}
+}
+
+func TestCFGRecognizeErrors(t *testing.T) {
+	g, _ := ParseCFG("E -> E + T | T\nT -> x | ( E )")
+	if ans, err := g.Recognize("x+"); ans || err != nil {
+		t.Error("On test: string of terminals outside the language, error: should be rejected without an error")
+	}
+	for _, w := range []string{"xy", "x+T"} {
+		if ans, err := g.Recognize(w); ans || err == nil {
+			t.Error("On test: string " + w + " with a symbol which is not a terminal, error: should have been reported")
+		}
+	}
+}
+
+func TestCFGParseForestSize(t *testing.T) {
+	//without binarizing, the root would have a family for every way of splitting
+	//the string into five parts
+	g, _ := ParseCFG("S -> A A A A A\nA -> a A | ε")
+	n := 40
+	f, err := g.ParseForest(strings.Repeat("a", n))
+	if err != nil {
+		t.Error("On test: forest of a long body, error: " + err.Error())
+		t.FailNow()
+	}
+	seen := map[*SPPFNode]bool{}
+	families := 0
+	var visit func(node *SPPFNode)
+	visit = func(node *SPPFNode) {
+		if seen[node] {
+			return
+		}
+		seen[node] = true
+		families += len(node.Families)
+		for _, family := range node.Families {
+			for _, child := range family.Children {
+				visit(child)
+			}
+		}
+	}
+	visit(f.Root)
+	if families > (n+1)*(n+1)*(n+1) {
+		t.Error("On test: forest of a long body, error: " + strconv.Itoa(families) + " families is more than cubic in the length of the string")
+	}
+	if !f.IsAmbiguous() {
+		t.Error("On test: forest of a long body, error: forest should be ambiguous")
+	}
+}
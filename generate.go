@@ -0,0 +1,399 @@
+package gocompute
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"strconv"
+)
+
+// GenerateMode selects how CFG.Generate produces strings.
+type GenerateMode int
+
+const (
+	// GenerateRandom expands variables top-down, choosing among their productions at random in proportion to their
+	// weights, within the depth limit.
+	GenerateRandom GenerateMode = iota
+	// GenerateUniform draws strings of exactly the given length, uniformly among the derivations of that length in
+	// the Chomsky normal form of the grammar. For an unambiguous grammar, this is uniform among the strings of the
+	// language of that length.
+	GenerateUniform
+	// GenerateCoverage produces a set of strings whose derivations together use every production of the grammar
+	// which can be used at all. Each string is built by the shortest derivation reaching a production not yet
+	// covered, so no randomness is involved and rng may be nil.
+	GenerateCoverage
+	// GenerateNegative produces near misses: strings which are not in the language, made by applying small random
+	// mutations to strings generated as by GenerateRandom until membership fails.
+	GenerateNegative
+)
+
+// DefaultGenerateDepth is the depth limit used by CFG.Generate when none is given.
+const DefaultGenerateDepth = 16
+
+// GenerateOptions configures CFG.Generate.
+//
+// Count is the number of strings to produce, 1 if it is 0; coverage mode produces as many as it needs instead.
+// MaxDepth limits the height of derivation trees in random and negative mode, counting the root as 1, and is
+// DefaultGenerateDepth if it is 0. Weights gives a relative weight to productions, keyed by their String, such as
+// "E -> E + T"; productions not listed weigh 1, and those with weight 0 are never chosen unless nothing else fits
+// the depth limit. Length is the length of the strings drawn in uniform mode. Mutations is the largest number of
+// mutations applied to each string in negative mode, 1 if it is 0; each mutation inserts, deletes or replaces a
+// terminal, or swaps two adjacent ones.
+type GenerateOptions struct {
+	Mode      GenerateMode
+	Count     int
+	MaxDepth  int
+	Weights   map[string]float64
+	Length    int
+	Mutations int
+}
+
+// Given a CFG g, a source of randomness rng and options opts, g.Generate(rng, opts) returns strings of L(g), or near
+// misses outside it, as selected by opts.Mode. Results are reproducible for a given seed of rng.
+func (g CFG) Generate(rng *rand.Rand, opts GenerateOptions) ([]string, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	if opts.Count < 0 || opts.MaxDepth < 0 || opts.Length < 0 || opts.Mutations < 0 {
+		return nil, errors.New("gocompute/cfg: generation options must not be negative")
+	}
+	if opts.Count == 0 {
+		opts.Count = 1
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = DefaultGenerateDepth
+	}
+	if opts.Mutations == 0 {
+		opts.Mutations = 1
+	}
+	if _, ok := g.generatingTrees()[g.start]; !ok {
+		return nil, errors.New("gocompute/cfg: language of CFG is empty")
+	}
+
+	switch opts.Mode {
+	case GenerateRandom:
+		var words []string
+		for i := 0; i < opts.Count; i++ {
+			t, err := g.randomTree(rng, opts)
+			if err != nil {
+				return nil, err
+			}
+			words = append(words, t.Yield())
+		}
+		return words, nil
+	case GenerateUniform:
+		return g.uniformStrings(rng, opts)
+	case GenerateCoverage:
+		var words []string
+		for _, t := range g.coverageTrees() {
+			words = append(words, t.Yield())
+		}
+		return words, nil
+	case GenerateNegative:
+		return g.nearMisses(rng, opts)
+	}
+	return nil, errors.New("gocompute/cfg: unknown generation mode")
+}
+
+//returns the height of a parse tree, counting terminals and ε nodes as 0
+func treeHeight(t *ParseTree) int {
+	h := 0
+	for _, child := range t.Children {
+		if c := treeHeight(child); c > h {
+			h = c
+		}
+	}
+	if t.Children == nil {
+		return 0
+	}
+	return h + 1
+}
+
+//expands the start variable at random, keeping to the depth limit by only choosing
+//productions whose least height still fits
+func (g CFG) randomTree(rng *rand.Rand, opts GenerateOptions) (*ParseTree, error) {
+	least := map[string]int{}
+	for v, t := range g.generatingTrees() {
+		least[v] = treeHeight(t)
+	}
+	if least[g.start] > opts.MaxDepth {
+		return nil, errors.New("gocompute/cfg: shortest derivation is deeper than the depth limit of " + strconv.Itoa(opts.MaxDepth))
+	}
+	byHead := g.productionsByHead()
+
+	var expand func(v string, depth int) *ParseTree
+	expand = func(v string, depth int) *ParseTree {
+		var fits []int
+		total := 0.0
+		for _, i := range byHead[v] {
+			h, ok := 1, true
+			for _, symbol := range g.productions[i].Body {
+				if g.variables.Contains(symbol) {
+					l, generating := least[symbol]
+					ok = ok && generating
+					if l+1 > h {
+						h = l + 1
+					}
+				}
+			}
+			if ok && depth+h-1 <= opts.MaxDepth {
+				fits = append(fits, i)
+				total += g.productionWeight(i, opts)
+			}
+		}
+		choice := fits[rng.Intn(len(fits))]
+		if total > 0 {
+			x := rng.Float64() * total
+			for _, i := range fits {
+				w := g.productionWeight(i, opts)
+				if w == 0 {
+					continue
+				}
+				choice = i
+				if x < w {
+					break
+				}
+				x -= w
+			}
+		}
+		t := &ParseTree{v, []*ParseTree{}}
+		for _, symbol := range g.productions[choice].Body {
+			if g.variables.Contains(symbol) {
+				t.Children = append(t.Children, expand(symbol, depth+1))
+			} else {
+				t.Children = append(t.Children, &ParseTree{symbol, nil})
+			}
+		}
+		return t
+	}
+	return expand(g.start, 1), nil
+}
+
+func (g CFG) productionWeight(i int, opts GenerateOptions) float64 {
+	if w, ok := opts.Weights[g.productions[i].String()]; ok && w >= 0 {
+		return w
+	}
+	return 1
+}
+
+//draws strings of the given length uniformly among derivations in Chomsky normal form
+func (g CFG) uniformStrings(rng *rand.Rand, opts GenerateOptions) ([]string, error) {
+	c := g.cnfRules()
+	n := opts.Length
+	if n == 0 {
+		for _, rule := range c.rules {
+			if rule.Head == c.start && len(rule.Body) == 0 {
+				return make([]string, opts.Count), nil
+			}
+		}
+		return nil, errors.New("gocompute/cfg: no strings of length 0")
+	}
+
+	//count[v][l] is the number of derivations of strings of length l from v
+	count := map[string][]*big.Int{}
+	for _, v := range c.orderedVariables() {
+		count[v] = make([]*big.Int, n+1)
+		for l := range count[v] {
+			count[v][l] = big.NewInt(0)
+		}
+	}
+	one := big.NewInt(1)
+	for _, rule := range c.rules {
+		if len(rule.Body) == 1 {
+			count[rule.Head][1].Add(count[rule.Head][1], one)
+		}
+	}
+	product := new(big.Int)
+	for l := 2; l <= n; l++ {
+		for _, rule := range c.rules {
+			if len(rule.Body) != 2 {
+				continue
+			}
+			for k := 1; k < l; k++ {
+				product.Mul(count[rule.Body[0]][k], count[rule.Body[1]][l-k])
+				count[rule.Head][l].Add(count[rule.Head][l], product)
+			}
+		}
+	}
+	if count[c.start][n].Sign() == 0 {
+		return nil, errors.New("gocompute/cfg: no strings of length " + strconv.Itoa(n))
+	}
+
+	//picks a derivation of length l from v, choosing each rule and split point in
+	//proportion to the number of derivations it leads to
+	var sample func(v string, l int) string
+	sample = func(v string, l int) string {
+		x := new(big.Int).Rand(rng, count[v][l])
+		for _, rule := range c.rules {
+			if rule.Head != v {
+				continue
+			}
+			if len(rule.Body) == 1 {
+				if l != 1 {
+					continue
+				}
+				if x.Sign() == 0 {
+					return rule.Body[0]
+				}
+				x.Sub(x, one)
+				continue
+			}
+			if len(rule.Body) != 2 {
+				continue
+			}
+			for k := 1; k < l; k++ {
+				product.Mul(count[rule.Body[0]][k], count[rule.Body[1]][l-k])
+				if x.Cmp(product) < 0 {
+					return sample(rule.Body[0], k) + sample(rule.Body[1], l-k)
+				}
+				x.Sub(x, product)
+			}
+		}
+		return ""
+	}
+	var words []string
+	for i := 0; i < opts.Count; i++ {
+		words = append(words, sample(c.start, n))
+	}
+	return words, nil
+}
+
+//returns parse trees which together use every production of g that occurs in some
+//derivation of a terminal string. each tree reaches the first production not yet
+//covered along the shortest path from the start variable, and fills every other
+//variable with its parse tree of least height.
+func (g CFG) coverageTrees() []*ParseTree {
+	least := g.generatingTrees()
+	key := map[string]int{}
+	for i, p := range g.productions {
+		key[p.key()] = i
+	}
+	usable := func(p Production) bool {
+		for _, symbol := range p.Body {
+			if _, ok := least[symbol]; g.variables.Contains(symbol) && !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	//for each variable reachable from the start through usable productions, the
+	//production and position through which it was first reached
+	type step struct {
+		production, position int
+	}
+	parent := map[string]step{g.start: {-1, -1}}
+	queue := []string{g.start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for i, p := range g.productions {
+			if p.Head != v || !usable(p) {
+				continue
+			}
+			for j, symbol := range p.Body {
+				if _, seen := parent[symbol]; g.variables.Contains(symbol) && !seen {
+					parent[symbol] = step{i, j}
+					queue = append(queue, symbol)
+				}
+			}
+		}
+	}
+
+	//applies production i to a new node, filling its variables with least trees
+	apply := func(i int) *ParseTree {
+		p := g.productions[i]
+		t := &ParseTree{p.Head, []*ParseTree{}}
+		for _, symbol := range p.Body {
+			if g.variables.Contains(symbol) {
+				t.Children = append(t.Children, least[symbol])
+			} else {
+				t.Children = append(t.Children, &ParseTree{symbol, nil})
+			}
+		}
+		return t
+	}
+	covered := make([]bool, len(g.productions))
+	var mark func(t *ParseTree)
+	mark = func(t *ParseTree) {
+		if t.Children == nil {
+			return
+		}
+		p := Production{t.Symbol, nil}
+		for _, child := range t.Children {
+			p.Body = append(p.Body, child.Symbol)
+			mark(child)
+		}
+		covered[key[p.key()]] = true
+	}
+
+	var trees []*ParseTree
+	for i, p := range g.productions {
+		if _, reachable := parent[p.Head]; covered[i] || !reachable || !usable(p) {
+			continue
+		}
+		t := apply(i)
+		for v := p.Head; parent[v].production >= 0; v = g.productions[parent[v].production].Head {
+			up := apply(parent[v].production)
+			up.Children[parent[v].position] = t
+			t = up
+		}
+		mark(t)
+		trees = append(trees, t)
+	}
+	return trees
+}
+
+//mutates random strings of L(g) until they fall outside it
+func (g CFG) nearMisses(rng *rand.Rand, opts GenerateOptions) ([]string, error) {
+	terminals := sortedAlphabet(g.terminals)
+	mutate := func(w []string) []string {
+		i := rng.Intn(len(w) + 1)
+		op := rng.Intn(4)
+		if len(w) == 0 {
+			op = 0
+		}
+		switch {
+		case op == 0 && len(terminals) > 0:
+			return append(append(append([]string{}, w[:i]...), terminals[rng.Intn(len(terminals))]), w[i:]...)
+		case op == 1 && i < len(w):
+			return append(append([]string{}, w[:i]...), w[i+1:]...)
+		case op == 2 && i < len(w) && len(terminals) > 0:
+			return append(append(append([]string{}, w[:i]...), terminals[rng.Intn(len(terminals))]), w[i+1:]...)
+		case op == 3 && i+1 < len(w):
+			swapped := append([]string{}, w...)
+			swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+			return swapped
+		}
+		return w
+	}
+
+	//a language containing every string near its members leaves nothing to find, so
+	//give up after a fixed number of attempts
+	var words []string
+	for attempts := 0; len(words) < opts.Count; attempts++ {
+		if attempts >= 100*opts.Count {
+			return nil, errors.New("gocompute/cfg: could not find " + strconv.Itoa(opts.Count) + " strings outside the language")
+		}
+		t, err := g.randomTree(rng, opts)
+		if err != nil {
+			return nil, err
+		}
+		w := []string{}
+		for _, r := range t.Yield() {
+			w = append(w, string(r))
+		}
+		for m := 1 + rng.Intn(opts.Mutations); m > 0; m-- {
+			w = mutate(w)
+		}
+		candidate := ""
+		for _, symbol := range w {
+			candidate += symbol
+		}
+		if member, _ := g.Recognize(candidate); !member {
+			words = append(words, candidate)
+		}
+	}
+	return words, nil
+}
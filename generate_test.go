@@ -0,0 +1,153 @@
+package gocompute
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+var generateTests = []struct {
+	text       string
+	length     int
+	descriptor string
+}{
+	{"S -> a S b | ε", 4, "grammar for a^n b^n"},
+	{"E -> E + T | T\nT -> T * F | F\nF -> ( E ) | x", 5, "expression grammar"},
+	{"S -> A B\nA -> a A | ε\nB -> b | B c", 5, "grammar with left recursion and ε-productions"},
+}
+
+func TestCFGGenerate(t *testing.T) {
+	for _, test := range generateTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		rng := rand.New(rand.NewSource(1))
+		modes := []struct {
+			opts   GenerateOptions
+			member bool
+			name   string
+		}{
+			{GenerateOptions{Mode: GenerateRandom, Count: 20, MaxDepth: 6}, true, "random"},
+			{GenerateOptions{Mode: GenerateUniform, Count: 20, Length: test.length}, true, "uniform"},
+			{GenerateOptions{Mode: GenerateCoverage}, true, "coverage"},
+			{GenerateOptions{Mode: GenerateNegative, Count: 20, Mutations: 2}, false, "negative"},
+		}
+		for _, mode := range modes {
+			words, err := g.Generate(rng, mode.opts)
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", " + mode.name + " mode, error: " + err.Error())
+				continue
+			}
+			if mode.opts.Count > 0 && len(words) != mode.opts.Count {
+				t.Error("On test: " + test.descriptor + ", " + mode.name + " mode, error: expected " + strconv.Itoa(mode.opts.Count) + " strings, got " + strconv.Itoa(len(words)))
+			}
+			for _, w := range words {
+				if ans, _ := g.Recognize(w); ans != mode.member {
+					t.Error("On test: " + test.descriptor + ", " + mode.name + " mode, error: membership of " + w + " should be " + strconv.FormatBool(mode.member))
+				}
+				if mode.opts.Mode == GenerateUniform && len(w) != mode.opts.Length {
+					t.Error("On test: " + test.descriptor + ", uniform mode, error: " + w + " has the wrong length")
+				}
+			}
+		}
+	}
+}
+
+func TestCFGGenerateDepth(t *testing.T) {
+	g, _ := ParseCFG("S -> ( S ) | S S | x")
+	rng := rand.New(rand.NewSource(2))
+	for depth := 1; depth <= 5; depth++ {
+		for i := 0; i < 20; i++ {
+			opts := GenerateOptions{Mode: GenerateRandom, MaxDepth: depth}
+			words, err := g.Generate(rng, opts)
+			if err != nil {
+				t.Error("On test: depth limit " + strconv.Itoa(depth) + ", error: " + err.Error())
+				t.FailNow()
+			}
+			//every level adds at most one pair of parentheses around x
+			nesting, deepest := 0, 0
+			for _, r := range words[0] {
+				switch r {
+				case '(':
+					nesting++
+				case ')':
+					nesting--
+				}
+				if nesting > deepest {
+					deepest = nesting
+				}
+			}
+			if deepest > depth-1 {
+				t.Error("On test: depth limit " + strconv.Itoa(depth) + ", error: " + words[0] + " nests too deeply")
+			}
+		}
+	}
+	h, _ := ParseCFG("S -> ( A )\nA -> ( B )\nB -> x")
+	if _, err := h.Generate(rng, GenerateOptions{MaxDepth: 2}); err == nil {
+		t.Error("On test: depth limit below the shortest derivation, error: should fail")
+	}
+}
+
+func TestCFGGenerateWeights(t *testing.T) {
+	g, _ := ParseCFG("S -> a | b | c")
+	rng := rand.New(rand.NewSource(3))
+	words, err := g.Generate(rng, GenerateOptions{Count: 200, Weights: map[string]float64{"S -> a": 0, "S -> b": 3}})
+	if err != nil {
+		t.Error("On test: production weights, error: " + err.Error())
+		t.FailNow()
+	}
+	counts := map[string]int{}
+	for _, w := range words {
+		counts[w]++
+	}
+	if counts["a"] != 0 || counts["b"] < 2*counts["c"] {
+		t.Error("On test: production weights, error: unexpected counts a=" + strconv.Itoa(counts["a"]) + " b=" + strconv.Itoa(counts["b"]) + " c=" + strconv.Itoa(counts["c"]))
+	}
+}
+
+func TestCFGGenerateUniform(t *testing.T) {
+	//the strings of length 4 over {a, b} with as many a's as b's, each with exactly one derivation
+	g, _ := ParseCFG("S -> a B | b A | ε\nA -> a S | b A A\nB -> b S | a B B")
+	rng := rand.New(rand.NewSource(4))
+	words, err := g.Generate(rng, GenerateOptions{Mode: GenerateUniform, Count: 600, Length: 4})
+	if err != nil {
+		t.Error("On test: uniform sampling, error: " + err.Error())
+		t.FailNow()
+	}
+	counts := map[string]int{}
+	for _, w := range words {
+		counts[w]++
+	}
+	if len(counts) != 6 {
+		t.Error("On test: uniform sampling, error: expected all 6 strings, got " + strconv.Itoa(len(counts)))
+	}
+	for w, n := range counts {
+		if n < 60 || n > 140 {
+			t.Error("On test: uniform sampling, error: string " + w + " drawn " + strconv.Itoa(n) + " times out of 600")
+		}
+	}
+	if _, err := g.Generate(rng, GenerateOptions{Mode: GenerateUniform, Length: 3}); err == nil {
+		t.Error("On test: uniform sampling, error: there are no strings of length 3")
+	}
+}
+
+func TestCFGGenerateCoverage(t *testing.T) {
+	g, _ := ParseCFG("S -> A | B c\nA -> a A | ε\nB -> b | d B\nC -> c\nD -> D d")
+	words, err := g.Generate(nil, GenerateOptions{Mode: GenerateCoverage})
+	if err != nil {
+		t.Error("On test: coverage, error: " + err.Error())
+		t.FailNow()
+	}
+	want := []string{"", "bc", "a", "dbc"}
+	if len(words) != len(want) {
+		t.Error("On test: coverage, error: expected " + strconv.Itoa(len(want)) + " strings, got " + strconv.Itoa(len(words)))
+		t.FailNow()
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Error("On test: coverage, error: expected " + want[i] + ", got " + words[i])
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"sort"
+)
+
+// A MergeStrategy selects how InferDFAWithStrategy chooses which pair of states to merge next.
+type MergeStrategy int
+
+const (
+	// RPNI merges each blue state into the first red state it is compatible with, visiting states in shortlex order
+	// of their access words.
+	RPNI MergeStrategy = iota
+	// EDSM (evidence-driven state merging) scores every compatible red/blue pair by the number of labeled states the
+	// merge would identify, and performs the merge with the highest score.
+	EDSM
+)
+
+//labels of prefix tree nodes
+const (
+	unlabeled = 0
+	positive  = 1
+	negative  = -1
+)
+
+//augmented prefix tree acceptor. nodes are ints; node 0 is the root. as states
+//are merged the tree becomes a general automaton, but a node is never merged
+//away while it is still reachable from a red state.
+type prefixTree struct {
+	children []map[string]int
+	label    []int
+	access   []string
+}
+
+func newPrefixTree(pos, neg []string) (*prefixTree, error) {
+	t := &prefixTree{[]map[string]int{{}}, []int{unlabeled}, []string{""}}
+	insert := func(w string, label int) error {
+		node := 0
+		for _, r := range w {
+			a := string(r)
+			next, ok := t.children[node][a]
+			if !ok {
+				next = len(t.label)
+				t.children = append(t.children, map[string]int{})
+				t.label = append(t.label, unlabeled)
+				t.access = append(t.access, t.access[node]+a)
+				t.children[node][a] = next
+			}
+			node = next
+		}
+		if t.label[node] != unlabeled && t.label[node] != label {
+			return errors.New("gocompute/infer: string " + w + " is both a positive and a negative sample")
+		}
+		t.label[node] = label
+		return nil
+	}
+	for _, w := range pos {
+		if err := insert(w, positive); err != nil {
+			return nil, err
+		}
+	}
+	for _, w := range neg {
+		if err := insert(w, negative); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *prefixTree) clone() *prefixTree {
+	c := &prefixTree{make([]map[string]int, len(t.children)), append([]int(nil), t.label...), t.access}
+	for i, m := range t.children {
+		c.children[i] = make(map[string]int, len(m))
+		for a, n := range m {
+			c.children[i][a] = n
+		}
+	}
+	return c
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//folds the subtree rooted at b into the automaton at r. returns the number of
+//pairs of equally labeled states identified, which is the EDSM score, and false
+//if some positive state is identified with a negative one.
+func (t *prefixTree) fold(r, b int) (int, bool) {
+	score := 0
+	if t.label[b] != unlabeled {
+		if t.label[r] == unlabeled {
+			t.label[r] = t.label[b]
+		} else if t.label[r] != t.label[b] {
+			return 0, false
+		} else {
+			score++
+		}
+	}
+	for _, a := range sortedKeys(t.children[b]) {
+		bc := t.children[b][a]
+		rc, ok := t.children[r][a]
+		if !ok {
+			t.children[r][a] = bc
+			continue
+		}
+		s, ok := t.fold(rc, bc)
+		if !ok {
+			return 0, false
+		}
+		score += s
+	}
+	return score, true
+}
+
+//merges blue state b into red state r on a copy of t. blue states hang directly
+//off a red state, so redirecting that one edge is enough before folding.
+func (t *prefixTree) merge(red []int, r, b int) (*prefixTree, int, bool) {
+	c := t.clone()
+	for _, p := range red {
+		for a, n := range c.children[p] {
+			if n == b {
+				c.children[p][a] = r
+			}
+		}
+	}
+	score, ok := c.fold(r, b)
+	return c, score, ok
+}
+
+//blue states are the successors of red states which are not themselves red,
+//in shortlex order of access word
+func (t *prefixTree) blue(red []int) []int {
+	isRed := map[int]bool{}
+	for _, r := range red {
+		isRed[r] = true
+	}
+	seen := map[int]bool{}
+	var blue []int
+	for _, r := range red {
+		for _, n := range t.children[r] {
+			if !isRed[n] && !seen[n] {
+				seen[n] = true
+				blue = append(blue, n)
+			}
+		}
+	}
+	sort.Sort(byAccess{blue, t.access})
+	return blue
+}
+
+//sorts prefix tree nodes in shortlex order of access word
+type byAccess struct {
+	nodes  []int
+	access []string
+}
+
+func (s byAccess) Len() int      { return len(s.nodes) }
+func (s byAccess) Swap(i, j int) { s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i] }
+func (s byAccess) Less(i, j int) bool {
+	a, b := s.access[s.nodes[i]], s.access[s.nodes[j]]
+	return len(a) < len(b) || (len(a) == len(b) && a < b)
+}
+
+// InferDFA builds a DFA over alphabet which accepts every string in pos and rejects every string in neg, using the
+// RPNI state merging algorithm. It is shorthand for InferDFAWithStrategy(pos, neg, alphabet, RPNI).
+func InferDFA(pos, neg []string, alphabet mapset.Set) (*DFA, error) {
+	return InferDFAWithStrategy(pos, neg, alphabet, RPNI)
+}
+
+// InferDFAWithStrategy builds a DFA over alphabet which accepts every string in pos and rejects every string in neg.
+// It starts from the prefix tree acceptor of the samples and repeatedly merges states, using the given strategy, so
+// long as the merge keeps the automaton consistent with the samples. If the samples contain a characteristic set for
+// some regular language, RPNI returns that language's minimal DFA.
+//
+// States of the returned DFA are ints. Transitions not determined by the samples lead to a rejecting sink state -1.
+// An error is returned if a string is both positive and negative, or uses a symbol outside alphabet.
+func InferDFAWithStrategy(pos, neg []string, alphabet mapset.Set, strategy MergeStrategy) (*DFA, error) {
+	for _, w := range append(append([]string(nil), pos...), neg...) {
+		for _, r := range w {
+			if !alphabet.Contains(string(r)) {
+				return nil, errors.New("gocompute/infer: sample " + w + " not in alphabet")
+			}
+		}
+	}
+	t, err := newPrefixTree(pos, neg)
+	if err != nil {
+		return nil, err
+	}
+
+	red := []int{0}
+	for {
+		blue := t.blue(red)
+		if len(blue) == 0 {
+			break
+		}
+
+		if strategy == EDSM {
+			var best *prefixTree
+			bestScore := -1
+			promoted := false
+			for _, b := range blue {
+				mergeable := false
+				for _, r := range red {
+					c, score, ok := t.merge(red, r, b)
+					if ok {
+						mergeable = true
+						if score > bestScore {
+							best, bestScore = c, score
+						}
+					}
+				}
+				//a blue state which merges with nothing must become red
+				if !mergeable {
+					red = append(red, b)
+					promoted = true
+					break
+				}
+			}
+			if !promoted {
+				t = best
+			}
+			continue
+		}
+
+		b := blue[0]
+		merged := false
+		for _, r := range red {
+			if c, _, ok := t.merge(red, r, b); ok {
+				t = c
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			red = append(red, b)
+		}
+	}
+
+	states := mapset.NewSet(-1)
+	accept := mapset.NewSet()
+	for _, r := range red {
+		states.Add(r)
+		if t.label[r] == positive {
+			accept.Add(r)
+		}
+	}
+	children := t.children
+	transition := func(state interface{}, input string) (nextState interface{}) {
+		q := state.(int)
+		if q < 0 {
+			return -1
+		}
+		if next, ok := children[q][input]; ok {
+			return next
+		}
+		return -1
+	}
+	return NewDFA(states, alphabet, transition, 0, accept)
+}
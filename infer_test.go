@@ -0,0 +1,81 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"testing"
+)
+
+//all strings of 0s and 1s of length at most n
+func allBinaryStrings(n int) []string {
+	words := []string{""}
+	for i := 0; i < len(words); i++ {
+		if len(words[i]) < n {
+			words = append(words, words[i]+"0", words[i]+"1")
+		}
+	}
+	return words
+}
+
+//splits the sample strings into those accepted and rejected by d
+func labelSamples(d *DFA, words []string) (pos, neg []string) {
+	for _, w := range words {
+		if ans, _ := d.Simulate(w); ans {
+			pos = append(pos, w)
+		} else {
+			neg = append(neg, w)
+		}
+	}
+	return pos, neg
+}
+
+var inferTests = []struct {
+	d          *DFA
+	err        error
+	descriptor string
+}{
+	{d1, d1err, "DFA accepting strings with even number of 1s"},
+	{d4, d4err, "DFA accepting strings with odd number of 0s"},
+	{d7, d7err, "DFA accepting all strings of 0s and 1s"},
+	{uniond1, uniond1err, "Union of DFAs accepting even number of 1s or even number of 0s"},
+	{interd1, interd1err, "Intersection of DFAs accepting even number of 1s and even number of 0s"},
+}
+
+func TestInferDFA(t *testing.T) {
+	samples := allBinaryStrings(5)
+	for _, strategy := range []MergeStrategy{RPNI, EDSM} {
+		for _, test := range inferTests {
+			if test.err != nil {
+				t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+				t.FailNow()
+			}
+			pos, neg := labelSamples(test.d, samples)
+			d, err := InferDFAWithStrategy(pos, neg, mapset.NewSet("0", "1"), strategy)
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+				t.FailNow()
+			}
+			for _, w := range pos {
+				if ans, _ := d.Simulate(w); !ans {
+					t.Error("On test: " + test.descriptor + ", error: inferred DFA should accept positive sample " + w)
+				}
+			}
+			for _, w := range neg {
+				if ans, _ := d.Simulate(w); ans {
+					t.Error("On test: " + test.descriptor + ", error: inferred DFA should reject negative sample " + w)
+				}
+			}
+			if w, ok := (DFAOracle{test.d}).Equivalent(d); !ok {
+				t.Error("On test: " + test.descriptor + ", error: inferred DFA disagrees with target on string " + w)
+			}
+		}
+	}
+}
+
+func TestInferDFAErrors(t *testing.T) {
+	if _, err := InferDFA([]string{"01"}, []string{"01"}, mapset.NewSet("0", "1")); err == nil {
+		t.Error("On test: conflicting samples, error: a string both accepted and rejected should be an error")
+	}
+	if _, err := InferDFA([]string{"012"}, nil, mapset.NewSet("0", "1")); err == nil {
+		t.Error("On test: sample outside alphabet, error: should be an error")
+	}
+}
@@ -0,0 +1,497 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"strings"
+)
+
+//computes FIRST for every variable of g. "" stands for ε.
+func (g CFG) firstSets() map[string]mapset.Set {
+	first := map[string]mapset.Set{}
+	for _, v := range g.orderedVariables() {
+		first[v] = mapset.NewSet()
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, p := range g.productions {
+			for elem := range g.firstOf(first, p.Body).Iter() {
+				if first[p.Head].Add(elem) {
+					changed = true
+				}
+			}
+		}
+	}
+	return first
+}
+
+//returns FIRST of a sequence of symbols, given FIRST of every variable
+func (g CFG) firstOf(first map[string]mapset.Set, symbols []string) mapset.Set {
+	result := mapset.NewSet()
+	for _, symbol := range symbols {
+		if g.terminals.Contains(symbol) {
+			result.Add(symbol)
+			return result
+		}
+		for elem := range first[symbol].Iter() {
+			if elem != "" {
+				result.Add(elem)
+			}
+		}
+		if !first[symbol].Contains("") {
+			return result
+		}
+	}
+	result.Add("")
+	return result
+}
+
+//computes FOLLOW for every variable of g. "" stands for the end of the input.
+func (g CFG) followSets(first map[string]mapset.Set) map[string]mapset.Set {
+	follow := map[string]mapset.Set{}
+	for _, v := range g.orderedVariables() {
+		follow[v] = mapset.NewSet()
+	}
+	follow[g.start].Add("")
+	for changed := true; changed; {
+		changed = false
+		for _, p := range g.productions {
+			for i, symbol := range p.Body {
+				if !g.variables.Contains(symbol) {
+					continue
+				}
+				rest := g.firstOf(first, p.Body[i+1:])
+				for _, elem := range rest.ToSlice() {
+					if elem != "" && follow[symbol].Add(elem) {
+						changed = true
+					}
+				}
+				if rest.Contains("") {
+					//p.Head may be symbol itself, so add from a snapshot
+					for _, elem := range follow[p.Head].ToSlice() {
+						if follow[symbol].Add(elem) {
+							changed = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return follow
+}
+
+// Given a CFG g, g.FirstSets() returns FIRST(A) for every variable A of g: the set of terminals which can begin a
+// string derived from A, together with "" if A derives ε.
+func (g CFG) FirstSets() (map[string]mapset.Set, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	return g.firstSets(), nil
+}
+
+// Given a CFG g, g.FollowSets() returns FOLLOW(A) for every variable A of g: the set of terminals which can come
+// right after A in a sentential form derived from the start variable, together with "" if A can come at the end of
+// one.
+func (g CFG) FollowSets() (map[string]mapset.Set, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	return g.followSets(g.firstSets()), nil
+}
+
+// LL1ConflictKind tells which sets two productions of an LL(1) conflict collide in.
+type LL1ConflictKind int
+
+const (
+	// FirstFirstConflict means that the lookahead is in FIRST of the bodies of at least two of the productions.
+	FirstFirstConflict LL1ConflictKind = iota
+	// FirstFollowConflict means that the lookahead is in FIRST of the body of at most one of the productions, and
+	// the others are chosen on it because their bodies derive ε and the lookahead is in FOLLOW of the variable.
+	FirstFollowConflict
+)
+
+// An LL1Conflict is a cell of an LL(1) parse table with more than one production: on the lookahead terminal
+// Lookahead, or the end of the input if Lookahead is "", a predictive parser expanding Variable could not choose
+// between Productions.
+type LL1Conflict struct {
+	Variable    string
+	Lookahead   string
+	Productions []Production
+	Kind        LL1ConflictKind
+}
+
+// String describes the conflict, such as `FIRST/FIRST conflict for E on "x" between E -> x and E -> x + E`.
+func (c LL1Conflict) String() string {
+	kind := "FIRST/FIRST"
+	if c.Kind == FirstFollowConflict {
+		kind = "FIRST/FOLLOW"
+	}
+	lookahead := "end of input"
+	if c.Lookahead != "" {
+		lookahead = "\"" + c.Lookahead + "\""
+	}
+	var productions []string
+	for _, p := range c.Productions {
+		productions = append(productions, p.String())
+	}
+	return kind + " conflict for " + c.Variable + " on " + lookahead + " between " + strings.Join(productions, " and ")
+}
+
+// An LL1Table is the parse table of a predictive parser for a CFG, giving the productions to expand each variable
+// with for each lookahead terminal. The lookahead "" stands for the end of the input.
+type LL1Table struct {
+	g     *CFG
+	first map[string]mapset.Set
+	table map[string]map[string][]int
+}
+
+// Given a CFG g, g.LL1Table() returns the LL(1) parse table of g. The production A -> α is in the cell for A and a
+// when a is in FIRST(α), or when α derives ε and a is in FOLLOW(A). g is LL(1) when no cell has more than one
+// production; the table of a grammar which is not is still returned, and its Conflicts explain why.
+func (g CFG) LL1Table() (*LL1Table, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	first := g.firstSets()
+	follow := g.followSets(first)
+	t := &LL1Table{&g, first, map[string]map[string][]int{}}
+	for _, v := range g.orderedVariables() {
+		t.table[v] = map[string][]int{}
+	}
+	for i, p := range g.productions {
+		lookaheads := g.firstOf(first, p.Body)
+		if lookaheads.Contains("") {
+			lookaheads.Remove("")
+			lookaheads = lookaheads.Union(follow[p.Head])
+		}
+		for _, a := range sortedAlphabet(lookaheads) {
+			t.table[p.Head][a] = append(t.table[p.Head][a], i)
+		}
+	}
+	return t, nil
+}
+
+// Entry returns the productions in the cell of t for variable and lookahead, in the order they appear in the grammar.
+func (t *LL1Table) Entry(variable, lookahead string) []Production {
+	var productions []Production
+	for _, i := range t.table[variable][lookahead] {
+		productions = append(productions, t.g.productions[i])
+	}
+	return productions
+}
+
+// Conflicts returns every cell of t with more than one production, by variable in the order in which String prints
+// them, then by lookahead in sorted order.
+func (t *LL1Table) Conflicts() []LL1Conflict {
+	var conflicts []LL1Conflict
+	for _, v := range t.g.orderedVariables() {
+		cells := mapset.NewSet()
+		for a := range t.table[v] {
+			cells.Add(a)
+		}
+		for _, a := range sortedAlphabet(cells) {
+			if len(t.table[v][a]) < 2 {
+				continue
+			}
+			c := LL1Conflict{v, a, t.Entry(v, a), FirstFollowConflict}
+			inFirst := 0
+			for _, p := range c.Productions {
+				if t.g.firstOf(t.first, p.Body).Contains(a) {
+					inFirst++
+				}
+			}
+			if inFirst >= 2 {
+				c.Kind = FirstFirstConflict
+			}
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+// IsLL1 reports whether t has no conflicts, so that the grammar it was built from is LL(1).
+func (t *LL1Table) IsLL1() bool {
+	return len(t.Conflicts()) == 0
+}
+
+// Given the LL(1) table t of a grammar and a string w, t.Parse(w) parses w top-down with a stack, choosing every
+// production from the next input symbol, and returns the parse tree. It fails if t has conflicts. If w is not in the
+// language, the error is a *ParseError giving the position at which the parser got stuck and the terminals it could
+// have accepted there.
+func (t *LL1Table) Parse(w string) (*ParseTree, error) {
+	if conflicts := t.Conflicts(); len(conflicts) > 0 {
+		return nil, errors.New("gocompute/cfg: grammar is not LL(1): " + conflicts[0].String())
+	}
+	input := make([]string, 0, len(w))
+	for _, r := range w {
+		input = append(input, string(r))
+	}
+	lookahead := func(pos int) string {
+		if pos < len(input) {
+			return input[pos]
+		}
+		return ""
+	}
+
+	//expected collects the terminals which could have been read at pos: those
+	//starting each variable expanded there, and any terminal failing to match
+	root := &ParseTree{t.g.start, nil}
+	stack := []*ParseTree{root}
+	pos := 0
+	expected := mapset.NewSet()
+	fail := func() error {
+		e := &ParseError{pos, lookahead(pos), sortedAlphabet(expected)}
+		if len(e.Expected) == 0 {
+			e.Expected = nil
+		}
+		return e
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		a := lookahead(pos)
+		if t.g.terminals.Contains(node.Symbol) {
+			if node.Symbol != a {
+				expected.Add(node.Symbol)
+				return nil, fail()
+			}
+			pos++
+			expected = mapset.NewSet()
+			continue
+		}
+		for b := range t.first[node.Symbol].Iter() {
+			if b != "" {
+				expected.Add(b)
+			}
+		}
+		cell := t.table[node.Symbol][a]
+		if len(cell) == 0 {
+			return nil, fail()
+		}
+		node.Children = []*ParseTree{}
+		for _, symbol := range t.g.productions[cell[0]].Body {
+			node.Children = append(node.Children, &ParseTree{symbol, nil})
+		}
+		for i := len(node.Children) - 1; i >= 0; i-- {
+			stack = append(stack, node.Children[i])
+		}
+	}
+	if pos < len(input) {
+		return nil, fail()
+	}
+	return root, nil
+}
+
+//reports whether some variable of g derives itself through unit productions alone
+func (g CFG) hasUnitCycle() bool {
+	units := map[string][]string{}
+	for _, p := range g.productions {
+		if len(p.Body) == 1 && g.variables.Contains(p.Body[0]) {
+			units[p.Head] = append(units[p.Head], p.Body[0])
+		}
+	}
+	for _, v := range g.orderedVariables() {
+		seen := map[string]bool{}
+		queue := units[v]
+		for len(queue) > 0 {
+			w := queue[0]
+			queue = queue[1:]
+			if w == v {
+				return true
+			}
+			if !seen[w] {
+				seen[w] = true
+				queue = append(queue, units[w]...)
+			}
+		}
+	}
+	return false
+}
+
+// Given a CFG g, g.EliminateLeftRecursion() returns a pointer to a new CFG recognizing L(g) in which no variable
+// derives a sentential form starting with itself. The variables A1, ..., An are ordered as String prints them, and
+// every production Ai -> Aj α with j < i is rewritten using the productions of Aj. The immediate left recursion
+// A -> A α | β left on each variable is then replaced by A -> β Z_A and Z_A -> α Z_A | ε for a fresh variable Z_A.
+//
+// This only works on grammars without ε-productions or cycles of unit productions, so if g has either, other than an
+// ε-production for a start variable appearing in no body, it is first put through RemoveEpsilon and RemoveUnit, with
+// a new start variable if needed.
+func (g CFG) EliminateLeftRecursion() (*CFG, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	r := g.rules()
+	startInBody := false
+	for _, p := range g.productions {
+		for _, symbol := range p.Body {
+			startInBody = startInBody || symbol == g.start
+		}
+	}
+	cleanup := g.hasUnitCycle()
+	for _, p := range g.productions {
+		cleanup = cleanup || (len(p.Body) == 0 && (p.Head != g.start || startInBody))
+	}
+	if cleanup {
+		if startInBody {
+			r.addStart()
+		}
+		r.removeEpsilon()
+		r.removeUnit()
+	}
+
+	order := r.orderedVariables()
+	rank := map[string]int{}
+	for i, v := range order {
+		rank[v] = i
+	}
+	bodies := map[string][][]string{}
+	for _, rule := range r.rules {
+		bodies[rule.Head] = append(bodies[rule.Head], rule.Body)
+	}
+
+	var extra []string
+	for i, v := range order {
+		//substituting may expose a new Aj with j < i, so repeat until none is left
+		for again := true; again; {
+			again = false
+			var rewritten [][]string
+			for _, body := range bodies[v] {
+				if j, ok := rank[firstSymbol(body)]; !ok || j >= i {
+					rewritten = append(rewritten, body)
+					continue
+				}
+				again = true
+				for _, prefix := range bodies[body[0]] {
+					rewritten = append(rewritten, append(append([]string(nil), prefix...), body[1:]...))
+				}
+			}
+			bodies[v] = rewritten
+		}
+
+		var alphas, betas [][]string
+		for _, body := range bodies[v] {
+			switch {
+			case firstSymbol(body) != v:
+				betas = append(betas, body)
+			case len(body) > 1:
+				alphas = append(alphas, body[1:])
+			}
+		}
+		if len(alphas) == 0 {
+			continue
+		}
+		if len(betas) == 0 {
+			return nil, errors.New("gocompute/cfg: variable " + v + " is left recursive and non-generating")
+		}
+		z := r.freshVariable("Z_" + v)
+		extra = append(extra, z)
+		bodies[v] = nil
+		for _, beta := range betas {
+			bodies[v] = append(bodies[v], append(append([]string(nil), beta...), z))
+		}
+		for _, alpha := range alphas {
+			bodies[z] = append(bodies[z], append(append([]string(nil), alpha...), z))
+		}
+		bodies[z] = append(bodies[z], nil)
+	}
+
+	var productions []Production
+	for _, v := range append(order, extra...) {
+		for _, body := range bodies[v] {
+			productions = append(productions, Production{v, body})
+		}
+	}
+	return NewCFG(r.variables, r.terminals, productions, r.start)
+}
+
+//returns the first symbol of a body, or "" for an empty body
+func firstSymbol(body []string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	return body[0]
+}
+
+// Given a CFG g, g.LeftFactor() returns a pointer to a new CFG recognizing L(g) in which no two productions of the
+// same variable have bodies starting with the same symbol. Whenever productions A -> α β1 | ... | α βk share the
+// longest common prefix α, they are replaced by A -> α F_A and F_A -> β1 | ... | βk for a fresh variable F_A, and
+// the new variables are factored in turn, getting fresh variables F_A1, F_A2 and so on.
+func (g CFG) LeftFactor() (*CFG, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/cfg: invalid CFG: " + err.Error())
+	}
+	r := g.rules()
+	order := r.orderedVariables()
+	bodies := map[string][][]string{}
+	for _, rule := range r.rules {
+		bodies[rule.Head] = append(bodies[rule.Head], rule.Body)
+	}
+
+	//order grows as fresh variables are added, and they are factored too. fresh
+	//variables are named after the variable of g they were split from.
+	roots := map[string]string{}
+	for _, v := range order {
+		roots[v] = v
+	}
+	for i := 0; i < len(order); i++ {
+		v := order[i]
+		for changed := true; changed; {
+			changed = false
+			for k, body := range bodies[v] {
+				if len(body) == 0 {
+					continue
+				}
+				var group []int
+				for m := k; m < len(bodies[v]); m++ {
+					if firstSymbol(bodies[v][m]) == body[0] {
+						group = append(group, m)
+					}
+				}
+				if len(group) < 2 {
+					continue
+				}
+				prefix := body
+				for _, m := range group[1:] {
+					n := 0
+					for n < len(prefix) && n < len(bodies[v][m]) && prefix[n] == bodies[v][m][n] {
+						n++
+					}
+					prefix = prefix[:n]
+				}
+				f := r.freshVariable("F_" + roots[v])
+				roots[f] = roots[v]
+				order = append(order, f)
+				inGroup := map[int]bool{}
+				for _, m := range group {
+					inGroup[m] = true
+					bodies[f] = append(bodies[f], append([]string(nil), bodies[v][m][len(prefix):]...))
+				}
+				var rewritten [][]string
+				for m, other := range bodies[v] {
+					switch {
+					case m == k:
+						rewritten = append(rewritten, append(append([]string(nil), prefix...), f))
+					case !inGroup[m]:
+						rewritten = append(rewritten, other)
+					}
+				}
+				bodies[v] = rewritten
+				changed = true
+				break
+			}
+		}
+	}
+
+	var productions []Production
+	for _, v := range order {
+		for _, body := range bodies[v] {
+			productions = append(productions, Production{v, body})
+		}
+	}
+	return NewCFG(r.variables, r.terminals, productions, r.start)
+}
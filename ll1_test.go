@@ -0,0 +1,217 @@
+package gocompute
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var firstFollowTests = []struct {
+	text       string
+	first      map[string]string
+	follow     map[string]string
+	descriptor string
+}{
+	{"E -> T R\nR -> + T R | ε\nT -> F Q\nQ -> * F Q | ε\nF -> ( E ) | x",
+		map[string]string{"E": "( x", "R": " +", "T": "( x", "Q": " *", "F": "( x"},
+		map[string]string{"E": " )", "R": " )", "T": " ) +", "Q": " ) +", "F": " ) * +"},
+		"expression grammar without left recursion"},
+	{"S -> A B c\nA -> a | ε\nB -> b | ε",
+		map[string]string{"S": "a b c", "A": " a", "B": " b"},
+		map[string]string{"S": "", "A": "b c", "B": "c"},
+		"grammar with nullable variables in sequence"},
+}
+
+func TestCFGFirstFollow(t *testing.T) {
+	for _, test := range firstFollowTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		first, _ := g.FirstSets()
+		follow, _ := g.FollowSets()
+		for v, want := range test.first {
+			if got := strings.Join(sortedAlphabet(first[v]), " "); got != want {
+				t.Error("On test: " + test.descriptor + ", error: FIRST(" + v + ") should be {" + want + "}, got {" + got + "}")
+			}
+		}
+		for v, want := range test.follow {
+			if got := strings.Join(sortedAlphabet(follow[v]), " "); got != want {
+				t.Error("On test: " + test.descriptor + ", error: FOLLOW(" + v + ") should be {" + want + "}, got {" + got + "}")
+			}
+		}
+	}
+}
+
+func TestCFGFollowRightRecursion(t *testing.T) {
+	g, err := ParseCFG("E -> T + E | T\nT -> x | ( E )")
+	if err != nil {
+		t.Error("On test: right recursive grammar, error: " + err.Error())
+		t.FailNow()
+	}
+	//FOLLOW(E) is added to itself, which used to deadlock
+	done := make(chan map[string]string)
+	go func() {
+		follow, _ := g.FollowSets()
+		done <- map[string]string{"E": strings.Join(sortedAlphabet(follow["E"]), " "), "T": strings.Join(sortedAlphabet(follow["T"]), " ")}
+	}()
+	select {
+	case got := <-done:
+		if got["E"] != " )" || got["T"] != " ) +" {
+			t.Error("On test: right recursive grammar, error: FOLLOW(E) should be { )} and FOLLOW(T) { ) +}, got {" + got["E"] + "} and {" + got["T"] + "}")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("On test: right recursive grammar, error: FOLLOW sets were not computed within 5 seconds")
+	}
+}
+
+var ll1ConflictTests = []struct {
+	text       string
+	conflicts  []string
+	descriptor string
+}{
+	{"E -> T R\nR -> + T R | ε\nT -> ( E ) | x", nil, "LL(1) expression grammar"},
+	{"E -> E + T | T\nT -> x", []string{`FIRST/FIRST conflict for E on "x" between E -> E + T and E -> T`}, "left recursive grammar"},
+	{"S -> a b | a c", []string{`FIRST/FIRST conflict for S on "a" between S -> a b and S -> a c`}, "grammar needing left factoring"},
+	{"S -> A a\nA -> a | ε", []string{`FIRST/FOLLOW conflict for A on "a" between A -> a and A -> ε`}, "grammar with a nullable variable followed by its own first terminal"},
+	{"S -> A | B\nA -> ε\nB -> ε", []string{`FIRST/FIRST conflict for S on end of input between S -> A and S -> B`}, "grammar with two nullable alternatives"},
+}
+
+func TestCFGLL1Conflicts(t *testing.T) {
+	for _, test := range ll1ConflictTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		table, err := g.LL1Table()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		var conflicts []string
+		for _, c := range table.Conflicts() {
+			conflicts = append(conflicts, c.String())
+		}
+		if strings.Join(conflicts, "\n") != strings.Join(test.conflicts, "\n") {
+			t.Error("On test: " + test.descriptor + ", error: unexpected conflicts\n" + strings.Join(conflicts, "\n"))
+		}
+		if table.IsLL1() != (len(test.conflicts) == 0) {
+			t.Error("On test: " + test.descriptor + ", error: IsLL1 disagrees with the conflicts")
+		}
+	}
+}
+
+var ll1ParseTests = []struct {
+	w          string
+	tree       string
+	position   int
+	expected   string
+	descriptor string
+}{
+	{"x", "E(T(x) R(ε))", 0, "", "single variable"},
+	{"x+(x)", "E(T(x) R(+ T(( E(T(x) R(ε)) )) R(ε)))", 0, "", "sum with parentheses"},
+	{"x+", "", 2, "( x", "input ending after an operator"},
+	{"x)", "", 1, "+", "unbalanced closing parenthesis"},
+	{"(x", "", 2, ") +", "missing closing parenthesis"},
+	{"xx", "", 1, "+", "two operands in a row"},
+}
+
+func TestLL1Parse(t *testing.T) {
+	g, _ := ParseCFG("E -> T R\nR -> + T R | ε\nT -> ( E ) | x")
+	table, err := g.LL1Table()
+	if err != nil {
+		t.Error("On test: LL(1) parsing, error: " + err.Error())
+		t.FailNow()
+	}
+	for _, test := range ll1ParseTests {
+		tree, err := table.Parse(test.w)
+		if test.tree != "" {
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			} else if tree.String() != test.tree {
+				t.Error("On test: " + test.descriptor + ", error: expected tree " + test.tree + ", got " + tree.String())
+			}
+			continue
+		}
+		e, ok := err.(*ParseError)
+		if !ok {
+			t.Error("On test: " + test.descriptor + ", error: expected a *ParseError")
+			continue
+		}
+		if e.Position != test.position || strings.Join(e.Expected, " ") != test.expected {
+			t.Error("On test: " + test.descriptor + ", error: unexpected parse error: " + e.Error())
+		}
+	}
+
+	h, _ := ParseCFG("S -> a b | a c")
+	conflicted, _ := h.LL1Table()
+	if _, err := conflicted.Parse("ab"); err == nil {
+		t.Error("On test: parsing with a conflicted table, error: should fail")
+	}
+}
+
+var ll1TransformTests = []struct {
+	text       string
+	transform  func(g *CFG) (*CFG, error)
+	result     string
+	descriptor string
+}{
+	{"E -> E + T | T\nT -> T * F | F\nF -> ( E ) | x", (*CFG).EliminateLeftRecursion,
+		"E -> T Z_E\nT -> F Z_T\nF -> ( E ) | x\nZ_E -> + T Z_E | ε\nZ_T -> * F Z_T | ε",
+		"immediate left recursion"},
+	{"S -> A a | b\nA -> S c | d", (*CFG).EliminateLeftRecursion,
+		"S -> A a | b\nA -> b c Z_A | d Z_A\nZ_A -> a c Z_A | ε",
+		"indirect left recursion"},
+	{"S -> a b c | a b d | a e | f", (*CFG).LeftFactor,
+		"S -> a F_S | f\nF_S -> b F_S1 | e\nF_S1 -> c | d",
+		"nested common prefixes"},
+	{"S -> i E t S | i E t S e S | a\nE -> b", (*CFG).LeftFactor,
+		"S -> i E t S F_S | a\nE -> b\nF_S -> ε | e S",
+		"dangling else"},
+}
+
+func TestCFGLL1Transforms(t *testing.T) {
+	for _, test := range ll1TransformTests {
+		g, err := ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		h, err := test.transform(g)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if h.String() != test.result {
+			t.Error("On test: " + test.descriptor + ", error: expected\n" + test.result + "\ngot\n" + h.String())
+		}
+		for _, w := range allStrings(sortedAlphabet(g.terminals), 5) {
+			want, _ := g.Recognize(w)
+			if got, _ := h.Recognize(w); got != want {
+				t.Error("On test: " + test.descriptor + ", error: transformed grammar disagrees on string " + w)
+			}
+		}
+	}
+}
+
+func TestCFGEliminateLeftRecursionHidden(t *testing.T) {
+	g, _ := ParseCFG("S -> A S a | b\nA -> ε | c")
+	h, err := g.EliminateLeftRecursion()
+	if err != nil {
+		t.Error("On test: left recursion hidden behind a nullable variable, error: " + err.Error())
+		t.FailNow()
+	}
+	for _, p := range h.productions {
+		if len(p.Body) > 0 && p.Body[0] == p.Head {
+			t.Error("On test: left recursion hidden behind a nullable variable, error: production " + p.String() + " is left recursive")
+		}
+	}
+	for _, w := range allStrings([]string{"a", "b", "c"}, 6) {
+		want, _ := g.Recognize(w)
+		if got, _ := h.Recognize(w); got != want {
+			t.Error("On test: left recursion hidden behind a nullable variable, error: transformed grammar disagrees on string " + w)
+		}
+	}
+}
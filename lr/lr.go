@@ -0,0 +1,646 @@
+// Package lr builds LR parse tables for context-free grammars from the gocompute package. It constructs the LR(0)
+// item automaton of a grammar, derives LR(0), SLR(1) or LALR(1) parse tables from it, reports their shift/reduce and
+// reduce/reduce conflicts together with an input prefix leading to each, and parses token streams with the
+// resulting shift-reduce parser.
+package lr
+
+import (
+	"errors"
+	"github.com/jophish/gocompute"
+	"github.com/jophish/golang-set"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//a production with a position in its body. productions are numbered as in the
+//augmented grammar, where production 0 is S' -> S.
+type item struct {
+	production int
+	dot        int
+}
+
+// An Automaton is the LR(0) item automaton of a grammar, augmented with a fresh start variable S' and the production
+// S' -> S. Each state is a set of items, productions with a dot marking how much of the body has been seen, closed
+// under adding B -> •γ whenever the dot is before B. State 0 is the closure of S' -> •S, and the transition from a
+// state on a symbol X moves the dot past X in every item where it is before X.
+type Automaton struct {
+	grammar     *gocompute.CFG
+	variables   mapset.Set
+	productions []gocompute.Production
+	start       string
+	states      [][]item
+	kernels     []int
+	gotos       []map[string]int
+	symbols     []string
+}
+
+//adds every item B -> •γ for a variable B after the dot in some item, to the kernel
+func (a *Automaton) closure(kernel []item) []item {
+	items := append([]item(nil), kernel...)
+	seen := map[item]bool{}
+	for _, it := range items {
+		seen[it] = true
+	}
+	for i := 0; i < len(items); i++ {
+		next, ok := a.next(items[i])
+		if !ok || !a.variables.Contains(next) {
+			continue
+		}
+		for k, p := range a.productions {
+			if p.Head == next && !seen[item{k, 0}] {
+				seen[item{k, 0}] = true
+				items = append(items, item{k, 0})
+			}
+		}
+	}
+	return items
+}
+
+//returns the symbol after the dot of an item, if any
+func (a *Automaton) next(it item) (string, bool) {
+	body := a.productions[it.production].Body
+	if it.dot < len(body) {
+		return body[it.dot], true
+	}
+	return "", false
+}
+
+func kernelKey(kernel []item) string {
+	parts := make([]string, len(kernel))
+	for i, it := range kernel {
+		parts[i] = strconv.Itoa(it.production) + "." + strconv.Itoa(it.dot)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+// NewAutomaton returns the LR(0) item automaton of g. States are numbered in the order they are discovered, from
+// state 0, taking the symbols after the dots of each state in the order they appear in its items.
+func NewAutomaton(g *gocompute.CFG) (*Automaton, error) {
+	ans, err := g.CheckCFG()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/lr: invalid CFG: " + err.Error())
+	}
+	start := g.Start() + "'"
+	for g.Variables().Contains(start) {
+		start += "'"
+	}
+	a := &Automaton{grammar: g, variables: g.Variables(), start: start}
+	a.productions = append([]gocompute.Production{{Head: start, Body: []string{g.Start()}}}, g.Productions()...)
+	a.symbols = append(sortedSymbols(g.Variables()), sortedSymbols(g.Terminals())...)
+
+	index := map[string]int{}
+	add := func(kernel []item) int {
+		key := kernelKey(kernel)
+		if i, ok := index[key]; ok {
+			return i
+		}
+		index[key] = len(a.states)
+		a.states = append(a.states, a.closure(kernel))
+		a.kernels = append(a.kernels, len(kernel))
+		a.gotos = append(a.gotos, map[string]int{})
+		return len(a.states) - 1
+	}
+	add([]item{{0, 0}})
+	for i := 0; i < len(a.states); i++ {
+		var order []string
+		moved := map[string][]item{}
+		for _, it := range a.states[i] {
+			if x, ok := a.next(it); ok {
+				if _, seen := moved[x]; !seen {
+					order = append(order, x)
+				}
+				moved[x] = append(moved[x], item{it.production, it.dot + 1})
+			}
+		}
+		for _, x := range order {
+			a.gotos[i][x] = add(moved[x])
+		}
+	}
+	return a, nil
+}
+
+func sortedSymbols(set mapset.Set) []string {
+	var symbols []string
+	for elem := range set.Iter() {
+		symbols = append(symbols, elem.(string))
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// NumStates returns the number of states of a.
+func (a *Automaton) NumStates() int {
+	return len(a.states)
+}
+
+// Goto returns the state a moves to from state on symbol, and false if there is no such transition.
+func (a *Automaton) Goto(state int, symbol string) (int, bool) {
+	next, ok := a.gotos[state][symbol]
+	return next, ok
+}
+
+// Items returns the items of state, kernel items first, written like "E -> E • + T".
+func (a *Automaton) Items(state int) []string {
+	var items []string
+	for _, it := range a.states[state] {
+		p := a.productions[it.production]
+		symbols := append(append(append([]string{}, p.Body[:it.dot]...), "•"), p.Body[it.dot:]...)
+		items = append(items, p.Head+" -> "+strings.Join(symbols, " "))
+	}
+	return items
+}
+
+// DFA returns a as a DFA over the variables and terminals of the grammar, whose states are the ints numbering the
+// states of a, together with a dead state -1 receiving every missing transition. Every state but -1 accepts, so the
+// DFA recognizes the viable prefixes of the grammar: the sequences of symbols which can be on the stack of a
+// shift-reduce parser. Since symbols are read one character at a time by DFA.Simulate, simulating it on a string only
+// makes sense when every variable is a single character.
+func (a *Automaton) DFA() (*gocompute.DFA, error) {
+	states := mapset.NewSet(-1)
+	for i := range a.states {
+		states.Add(i)
+	}
+	accept := states.Clone()
+	accept.Remove(-1)
+	alphabet := mapset.NewSet()
+	for _, symbol := range a.symbols {
+		alphabet.Add(symbol)
+	}
+	transition := func(state interface{}, input string) interface{} {
+		if i := state.(int); i >= 0 {
+			if next, ok := a.gotos[i][input]; ok {
+				return next
+			}
+		}
+		return -1
+	}
+	return gocompute.NewDFA(states, alphabet, transition, 0, accept)
+}
+
+//returns a shortest string of terminals derived by each variable, preferring
+//productions in the order they appear. variables which derive no string are left out.
+func (a *Automaton) yields() map[string][]string {
+	yields := map[string][]string{}
+	for changed := true; changed; {
+		changed = false
+		for _, p := range a.productions {
+			yield := []string{}
+			for _, x := range p.Body {
+				y, ok := yields[x]
+				if !a.variables.Contains(x) {
+					y, ok = []string{x}, true
+				}
+				if !ok {
+					yield = nil
+					break
+				}
+				yield = append(yield, y...)
+			}
+			if current, ok := yields[p.Head]; yield != nil && (!ok || len(yield) < len(current)) {
+				yields[p.Head] = yield
+				changed = true
+			}
+		}
+	}
+	return yields
+}
+
+//returns a shortest string of terminals leading from state 0 to each state, reading
+//each variable on the way as a shortest string it derives and preferring symbols in
+//sorted order. states which only a variable deriving no string leads to get nil.
+func (a *Automaton) prefixes() [][]string {
+	yields := a.yields()
+	prefixes := make([][]string, len(a.states))
+	found := make([]bool, len(a.states))
+	done := make([]bool, len(a.states))
+	prefixes[0], found[0] = []string{}, true
+	for {
+		//take the nearest state not done yet, as in Dijkstra's algorithm
+		i := -1
+		for j := range a.states {
+			if found[j] && !done[j] && (i < 0 || len(prefixes[j]) < len(prefixes[i])) {
+				i = j
+			}
+		}
+		if i < 0 {
+			return prefixes
+		}
+		done[i] = true
+		for _, x := range a.symbols {
+			next, ok := a.gotos[i][x]
+			yield, derives := yields[x]
+			if !a.variables.Contains(x) {
+				yield, derives = []string{x}, true
+			}
+			if !ok || !derives || done[next] {
+				continue
+			}
+			if !found[next] || len(prefixes[i])+len(yield) < len(prefixes[next]) {
+				found[next] = true
+				prefixes[next] = append(append([]string{}, prefixes[i]...), yield...)
+			}
+		}
+	}
+}
+
+// Method selects how an LR parse table chooses the lookaheads on which to reduce.
+type Method int
+
+const (
+	// LR0 reduces by a completed item on every lookahead.
+	LR0 Method = iota
+	// SLR1 reduces by a completed item A -> α• on the lookaheads in FOLLOW(A).
+	SLR1
+	// LALR1 reduces by a completed item on the lookaheads which can follow it in the state, computed by
+	// propagating lookaheads through the LR(0) automaton as in the LALR(1) construction of Aho, Sethi and Ullman.
+	LALR1
+)
+
+// String returns the name of the method, such as "SLR(1)".
+func (m Method) String() string {
+	switch m {
+	case LR0:
+		return "LR(0)"
+	case SLR1:
+		return "SLR(1)"
+	}
+	return "LALR(1)"
+}
+
+// ActionKind is the kind of an entry of an LR parse table.
+type ActionKind int
+
+const (
+	// Shift pushes the lookahead and moves to State.
+	Shift ActionKind = iota
+	// Reduce pops the body of Production and pushes its head.
+	Reduce
+	// Accept ends the parse successfully.
+	Accept
+)
+
+// An Action is an entry of an LR parse table.
+type Action struct {
+	Kind       ActionKind
+	State      int
+	Production gocompute.Production
+}
+
+// String describes the action, such as "shift to 4" or "reduce E -> E + T".
+func (a Action) String() string {
+	switch a.Kind {
+	case Shift:
+		return "shift to " + strconv.Itoa(a.State)
+	case Reduce:
+		return "reduce " + a.Production.String()
+	}
+	return "accept"
+}
+
+// ConflictKind tells whether a conflict involves a shift.
+type ConflictKind int
+
+const (
+	// ShiftReduce means that the parser could both shift the lookahead and reduce.
+	ShiftReduce ConflictKind = iota
+	// ReduceReduce means that the parser could reduce by two or more productions, and cannot shift.
+	ReduceReduce
+)
+
+// A Conflict is an entry of an LR parse table with more than one action: in State, on the lookahead terminal
+// Lookahead, or the end of the input if Lookahead is "", the parser could take any of Actions. Prefix is a string of
+// terminals which brings the parser from the initial state to State, found by taking a shortest path through the
+// automaton and reading each variable on it as a shortest string it derives, so Prefix followed by Lookahead is an
+// example input on which the parser gets stuck. If every path to State reads a variable which derives no string, no
+// input reaches it and Prefix is nil.
+type Conflict struct {
+	State     int
+	Lookahead string
+	Actions   []Action
+	Kind      ConflictKind
+	Prefix    []string
+}
+
+// String describes the conflict, such as `shift/reduce conflict in state 5 after x + x on "+": shift to 4, reduce
+// E -> E + E`.
+func (c Conflict) String() string {
+	kind := "shift/reduce"
+	if c.Kind == ReduceReduce {
+		kind = "reduce/reduce"
+	}
+	after := "at the start"
+	switch {
+	case len(c.Prefix) > 0:
+		after = "after " + strings.Join(c.Prefix, " ")
+	case c.Prefix == nil:
+		after = "which no input reaches"
+	}
+	lookahead := "end of input"
+	if c.Lookahead != "" {
+		lookahead = "\"" + c.Lookahead + "\""
+	}
+	var actions []string
+	for _, a := range c.Actions {
+		actions = append(actions, a.String())
+	}
+	return kind + " conflict in state " + strconv.Itoa(c.State) + " " + after + " on " + lookahead + ": " + strings.Join(actions, ", ")
+}
+
+// A Table is an LR parse table: the actions of a shift-reduce parser for each state of an LR(0) automaton and each
+// lookahead, where the lookahead "" stands for the end of the input, and the state to move to after reducing to a
+// variable.
+type Table struct {
+	Method    Method
+	automaton *Automaton
+	actions   []map[string][]Action
+}
+
+// NewTable builds the parse table of g with the given method. The table of a grammar which is not LR(0), SLR(1) or
+// LALR(1) respectively is still returned, and its Conflicts explain why.
+func NewTable(g *gocompute.CFG, method Method) (*Table, error) {
+	if method < LR0 || method > LALR1 {
+		return nil, errors.New("gocompute/lr: unknown method")
+	}
+	a, err := NewAutomaton(g)
+	if err != nil {
+		return nil, err
+	}
+	t := &Table{method, a, make([]map[string][]Action, len(a.states))}
+	terminals := append(sortedSymbols(g.Terminals()), "")
+
+	//the lookaheads on which each item of each state is reduced
+	var lookaheads func(state int, it item) []string
+	switch method {
+	case LR0:
+		lookaheads = func(state int, it item) []string {
+			return terminals
+		}
+	case SLR1:
+		follow, err := g.FollowSets()
+		if err != nil {
+			return nil, err
+		}
+		lookaheads = func(state int, it item) []string {
+			return sortedSymbols(follow[a.productions[it.production].Head])
+		}
+	case LALR1:
+		lalr := a.lalrLookaheads()
+		lookaheads = func(state int, it item) []string {
+			return sortedSymbols(lalr[state][it])
+		}
+	}
+
+	for i, items := range a.states {
+		t.actions[i] = map[string][]Action{}
+		for _, x := range a.symbols {
+			if next, ok := a.gotos[i][x]; ok && g.Terminals().Contains(x) {
+				t.actions[i][x] = append(t.actions[i][x], Action{Shift, next, gocompute.Production{}})
+			}
+		}
+		for _, it := range items {
+			if _, ok := a.next(it); ok {
+				continue
+			}
+			if it.production == 0 {
+				t.actions[i][""] = append(t.actions[i][""], Action{Accept, 0, gocompute.Production{}})
+				continue
+			}
+			for _, b := range lookaheads(i, it) {
+				t.actions[i][b] = append(t.actions[i][b], Action{Reduce, 0, a.productions[it.production]})
+			}
+		}
+	}
+	return t, nil
+}
+
+//a lookahead standing for whatever follows an item, used to find where lookaheads
+//propagate. terminals are single characters, so it cannot be one.
+const propagated = "<#>"
+
+//computes the LALR(1) lookaheads of every item of every state. lookaheads of kernel
+//items are found by discovering which are generated spontaneously and which
+//propagate from one kernel item to another, then propagating to a fixpoint. the
+//lookaheads of the other items follow by closing each state once more.
+func (a *Automaton) lalrLookaheads() []map[item]mapset.Set {
+	first, _ := a.grammar.FirstSets()
+	firstOf := func(symbols []string, lookahead string) mapset.Set {
+		result := mapset.NewSet()
+		for _, symbol := range symbols {
+			if !a.variables.Contains(symbol) {
+				result.Add(symbol)
+				return result
+			}
+			for elem := range first[symbol].Iter() {
+				if elem != "" {
+					result.Add(elem)
+				}
+			}
+			if !first[symbol].Contains("") {
+				return result
+			}
+		}
+		result.Add(lookahead)
+		return result
+	}
+	//the LR(1) closure of items with sets of lookaheads
+	closure1 := func(kernel map[item]mapset.Set) map[item]mapset.Set {
+		items := map[item]mapset.Set{}
+		var queue []item
+		for it, set := range kernel {
+			items[it] = set.Clone()
+			queue = append(queue, it)
+		}
+		for len(queue) > 0 {
+			it := queue[0]
+			queue = queue[1:]
+			x, ok := a.next(it)
+			if !ok || !a.variables.Contains(x) {
+				continue
+			}
+			rest := a.productions[it.production].Body[it.dot+1:]
+			for k, p := range a.productions {
+				if p.Head != x {
+					continue
+				}
+				target := item{k, 0}
+				if items[target] == nil {
+					items[target] = mapset.NewSet()
+				}
+				grew := false
+				//target may be it itself, so add from a snapshot
+				for _, b := range items[it].ToSlice() {
+					for _, elem := range firstOf(rest, b.(string)).ToSlice() {
+						if items[target].Add(elem) {
+							grew = true
+						}
+					}
+				}
+				if grew {
+					queue = append(queue, target)
+				}
+			}
+		}
+		return items
+	}
+
+	type kernelItem struct {
+		state int
+		it    item
+	}
+	lookaheads := map[kernelItem]mapset.Set{}
+	propagates := map[kernelItem][]kernelItem{}
+	for i, items := range a.states {
+		for _, k := range items[:a.kernels[i]] {
+			lookaheads[kernelItem{i, k}] = mapset.NewSet()
+		}
+	}
+	lookaheads[kernelItem{0, item{0, 0}}].Add("")
+	for i, items := range a.states {
+		for _, k := range items[:a.kernels[i]] {
+			from := kernelItem{i, k}
+			for it, set := range closure1(map[item]mapset.Set{k: mapset.NewSet(propagated)}) {
+				x, ok := a.next(it)
+				if !ok {
+					continue
+				}
+				to := kernelItem{a.gotos[i][x], item{it.production, it.dot + 1}}
+				for b := range set.Iter() {
+					if b == propagated {
+						propagates[from] = append(propagates[from], to)
+					} else {
+						lookaheads[to].Add(b)
+					}
+				}
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for from, targets := range propagates {
+			for _, to := range targets {
+				for _, b := range lookaheads[from].ToSlice() {
+					if lookaheads[to].Add(b) {
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	result := make([]map[item]mapset.Set, len(a.states))
+	for i, items := range a.states {
+		kernel := map[item]mapset.Set{}
+		for _, k := range items[:a.kernels[i]] {
+			kernel[k] = lookaheads[kernelItem{i, k}]
+		}
+		result[i] = closure1(kernel)
+	}
+	return result
+}
+
+// Automaton returns the LR(0) automaton t was built from.
+func (t *Table) Automaton() *Automaton {
+	return t.automaton
+}
+
+// Action returns the actions of t in state on lookahead, shifts first and then reductions in the order their
+// productions appear in the grammar.
+func (t *Table) Action(state int, lookahead string) []Action {
+	return append([]Action(nil), t.actions[state][lookahead]...)
+}
+
+// Conflicts returns every entry of t with more than one action, by state and then by lookahead in sorted order.
+func (t *Table) Conflicts() []Conflict {
+	var conflicts []Conflict
+	prefixes := t.automaton.prefixes()
+	for i, actions := range t.actions {
+		lookaheads := mapset.NewSet()
+		for b := range actions {
+			lookaheads.Add(b)
+		}
+		for _, b := range sortedSymbols(lookaheads) {
+			if len(actions[b]) < 2 {
+				continue
+			}
+			c := Conflict{i, b, t.Action(i, b), ReduceReduce, prefixes[i]}
+			if actions[b][0].Kind == Shift {
+				c.Kind = ShiftReduce
+			}
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+// Parse runs the shift-reduce parser of t over a stream of tokens, each a terminal of the grammar, and returns the
+// parse tree. It fails if t has conflicts. If the tokens are not in the language, the error is a
+// *gocompute.ParseError giving the number of tokens shifted before the parser got stuck and the terminals it could
+// have accepted there.
+func (t *Table) Parse(tokens []string) (*gocompute.ParseTree, error) {
+	if conflicts := t.Conflicts(); len(conflicts) > 0 {
+		return nil, errors.New("gocompute/lr: grammar is not " + t.Method.String() + ": " + conflicts[0].String())
+	}
+	states := []int{0}
+	var trees []*gocompute.ParseTree
+	pos := 0
+	//the stack right after the last shift, from which to work out what was expected
+	//when the parser gets stuck, since reductions made since then depended on the lookahead
+	shifted := []int{0}
+	for {
+		lookahead := ""
+		if pos < len(tokens) {
+			lookahead = tokens[pos]
+		}
+		state := states[len(states)-1]
+		actions := t.actions[state][lookahead]
+		if len(actions) == 0 {
+			e := &gocompute.ParseError{Position: pos, Found: lookahead}
+			for _, b := range sortedSymbols(t.automaton.grammar.Terminals()) {
+				if t.shifts(shifted, b) {
+					e.Expected = append(e.Expected, b)
+				}
+			}
+			return nil, e
+		}
+		action := actions[0]
+		switch action.Kind {
+		case Accept:
+			return trees[0], nil
+		case Shift:
+			trees = append(trees, &gocompute.ParseTree{Symbol: lookahead})
+			states = append(states, action.State)
+			shifted = append([]int(nil), states...)
+			pos++
+		case Reduce:
+			n := len(action.Production.Body)
+			node := &gocompute.ParseTree{Symbol: action.Production.Head, Children: []*gocompute.ParseTree{}}
+			node.Children = append(node.Children, trees[len(trees)-n:]...)
+			trees = trees[:len(trees)-n]
+			states = states[:len(states)-n]
+			next, _ := t.automaton.Goto(states[len(states)-1], action.Production.Head)
+			trees = append(trees, node)
+			states = append(states, next)
+		}
+	}
+}
+
+//reports whether the parser, with the given stack of states, would shift the
+//terminal b after making the reductions the table calls for
+func (t *Table) shifts(stack []int, b string) bool {
+	stack = append([]int(nil), stack...)
+	for {
+		actions := t.actions[stack[len(stack)-1]][b]
+		if len(actions) == 0 {
+			return false
+		}
+		if actions[0].Kind != Reduce {
+			return actions[0].Kind == Shift
+		}
+		p := actions[0].Production
+		stack = stack[:len(stack)-len(p.Body)]
+		next, _ := t.automaton.Goto(stack[len(stack)-1], p.Head)
+		stack = append(stack, next)
+	}
+}
@@ -0,0 +1,218 @@
+package lr
+
+import (
+	"github.com/jophish/gocompute"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const expressionGrammar = "E -> E + T | T\nT -> T * F | F\nF -> ( E ) | x"
+
+var conflictTests = []struct {
+	text       string
+	method     Method
+	conflicts  []string
+	descriptor string
+}{
+	{expressionGrammar, LR0, []string{
+		`shift/reduce conflict in state 2 after x on "*": shift to 7, reduce E -> T`,
+		`shift/reduce conflict in state 9 after x + x on "*": shift to 7, reduce E -> E + T`,
+	}, "expression grammar, which is not LR(0)"},
+	{expressionGrammar, SLR1, nil, "expression grammar with SLR(1)"},
+	{expressionGrammar, LALR1, nil, "expression grammar with LALR(1)"},
+	{"S -> L = R | R\nL -> * R | x\nR -> L", SLR1, []string{
+		`shift/reduce conflict in state 2 after x on "=": shift to 6, reduce R -> L`,
+	}, "assignment grammar, which is LALR(1) but not SLR(1)"},
+	{"S -> L = R | R\nL -> * R | x\nR -> L", LALR1, nil, "assignment grammar with LALR(1)"},
+	{"S -> a A d | b B d | a B e | b A e\nA -> c\nB -> c", LALR1, []string{
+		`reduce/reduce conflict in state 6 after a c on "d": reduce A -> c, reduce B -> c`,
+		`reduce/reduce conflict in state 6 after a c on "e": reduce A -> c, reduce B -> c`,
+	}, "grammar which is LR(1) but not LALR(1)"},
+	{"E -> E + E | x", LALR1, []string{
+		`shift/reduce conflict in state 4 after x + x on "+": shift to 3, reduce E -> E + E`,
+	}, "ambiguous grammar"},
+	{"S -> A B c | A B d\nA -> ( A ) | x x\nB -> x | ε", LR0, []string{
+		`shift/reduce conflict in state 2 after x x on "x": shift to 6, reduce B -> ε`,
+	}, "grammar whose conflict is reached through a variable deriving two tokens"},
+	{"S -> a | U\nU -> U c | U c c", LR0, []string{
+		`shift/reduce conflict in state 3 which no input reaches on "c": shift to 4, reduce S -> U`,
+		`shift/reduce conflict in state 4 which no input reaches on "c": shift to 5, reduce U -> U c`,
+	}, "grammar whose conflicts are reached only through a variable deriving no string"},
+}
+
+//reports whether some choice of the actions of t, on the tokens followed by
+//lookahead, leaves the parser in state with all the tokens read
+func reaches(t *Table, tokens []string, state int, lookahead string) bool {
+	seen := map[string]bool{}
+	var search func(stack []int, pos int) bool
+	search = func(stack []int, pos int) bool {
+		key := strconv.Itoa(pos)
+		for _, i := range stack {
+			key += " " + strconv.Itoa(i)
+		}
+		if seen[key] || len(stack) > 2*len(tokens)+t.Automaton().NumStates() {
+			return false
+		}
+		seen[key] = true
+		top := stack[len(stack)-1]
+		if pos == len(tokens) && top == state {
+			return true
+		}
+		b := lookahead
+		if pos < len(tokens) {
+			b = tokens[pos]
+		}
+		for _, action := range t.Action(top, b) {
+			switch {
+			case action.Kind == Shift && pos < len(tokens):
+				if search(append(append([]int{}, stack...), action.State), pos+1) {
+					return true
+				}
+			case action.Kind == Reduce:
+				rest := stack[:len(stack)-len(action.Production.Body)]
+				next, _ := t.Automaton().Goto(rest[len(rest)-1], action.Production.Head)
+				if search(append(append([]int{}, rest...), next), pos) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return search([]int{0}, 0)
+}
+
+func TestConflicts(t *testing.T) {
+	for _, test := range conflictTests {
+		g, err := gocompute.ParseCFG(test.text)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		table, err := NewTable(g, test.method)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		var conflicts []string
+		for _, c := range table.Conflicts() {
+			conflicts = append(conflicts, c.String())
+			if c.Prefix != nil && !reaches(table, c.Prefix, c.State, c.Lookahead) {
+				t.Error("On test: " + test.descriptor + ", error: " + strings.Join(c.Prefix, " ") + " does not lead to state " + strconv.Itoa(c.State))
+			}
+		}
+		if strings.Join(conflicts, "\n") != strings.Join(test.conflicts, "\n") {
+			t.Error("On test: " + test.descriptor + ", error: unexpected conflicts\n" + strings.Join(conflicts, "\n"))
+		}
+	}
+}
+
+func TestLeftRecursionTerminates(t *testing.T) {
+	g, _ := gocompute.ParseCFG(expressionGrammar)
+	for _, method := range []Method{SLR1, LALR1} {
+		//lookaheads of left recursive items propagate to themselves, which used to deadlock
+		done := make(chan error)
+		go func() {
+			_, err := NewTable(g, method)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Error("On test: left recursive grammar with " + method.String() + ", error: " + err.Error())
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("On test: left recursive grammar with " + method.String() + ", error: table was not built within 5 seconds")
+		}
+	}
+}
+
+var parseTests = []struct {
+	tokens     string
+	tree       string
+	position   int
+	expected   string
+	descriptor string
+}{
+	{"x", "E(T(F(x)))", 0, "", "single operand"},
+	{"x+x*x", "E(E(T(F(x))) + T(T(F(x)) * F(x)))", 0, "", "multiplication binding tighter than addition"},
+	{"(x+x)*x", "E(T(T(F(( E(E(T(F(x))) + T(F(x))) ))) * F(x)))", 0, "", "parentheses"},
+	{"x+", "", 2, "( x", "input ending after an operator"},
+	{"x)", "", 1, "* +", "unbalanced closing parenthesis"},
+	{"xx", "", 1, "* +", "two operands in a row"},
+	{"x-x", "", 1, "* +", "token which is not a terminal"},
+}
+
+func TestParse(t *testing.T) {
+	g, _ := gocompute.ParseCFG(expressionGrammar)
+	for _, method := range []Method{SLR1, LALR1} {
+		table, err := NewTable(g, method)
+		if err != nil {
+			t.Error("On test: parsing with " + method.String() + ", error: " + err.Error())
+			t.FailNow()
+		}
+		for _, test := range parseTests {
+			tree, err := table.Parse(strings.Split(test.tokens, ""))
+			if test.tree != "" {
+				if err != nil {
+					t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+				} else if tree.String() != test.tree {
+					t.Error("On test: " + test.descriptor + ", error: expected tree " + test.tree + ", got " + tree.String())
+				}
+				continue
+			}
+			e, ok := err.(*gocompute.ParseError)
+			if !ok {
+				t.Error("On test: " + test.descriptor + ", error: expected a *ParseError")
+				continue
+			}
+			if e.Position != test.position || strings.Join(e.Expected, " ") != test.expected {
+				t.Error("On test: " + test.descriptor + ", error: unexpected parse error: " + e.Error())
+			}
+		}
+	}
+
+	h, _ := gocompute.ParseCFG("S -> ( S ) S | ε")
+	table, _ := NewTable(h, LALR1)
+	tree, err := table.Parse(strings.Split("(())", ""))
+	if err != nil {
+		t.Error("On test: parsing with ε-productions, error: " + err.Error())
+	} else if tree.String() != "S(( S(( S(ε) ) S(ε)) ) S(ε))" {
+		t.Error("On test: parsing with ε-productions, error: unexpected tree " + tree.String())
+	}
+
+	lr0, _ := NewTable(g, LR0)
+	if _, err := lr0.Parse([]string{"x"}); err == nil {
+		t.Error("On test: parsing with a conflicted table, error: should fail")
+	}
+}
+
+func TestAutomatonDFA(t *testing.T) {
+	g, _ := gocompute.ParseCFG("S -> ( S ) S | ε")
+	a, err := NewAutomaton(g)
+	if err != nil {
+		t.Error("On test: viable prefixes, error: " + err.Error())
+		t.FailNow()
+	}
+	if a.Items(0)[0] != "S' -> • S" {
+		t.Error("On test: viable prefixes, error: unexpected first item " + a.Items(0)[0])
+	}
+	d, err := a.DFA()
+	if err != nil {
+		t.Error("On test: viable prefixes, error: " + err.Error())
+		t.FailNow()
+	}
+	viable := map[string]bool{"": true, "S": true, "(": true, "((": true, "(S)": true, "(S)(": true, "(S)S": true, ")": false, "S(": false, "SS": false, "(S))": false}
+	for w, want := range viable {
+		if got, _ := d.Simulate(w); got != want {
+			t.Error("On test: viable prefixes, error: wrong answer for prefix " + w)
+		}
+	}
+	m, err := d.Minimize()
+	if err != nil {
+		t.Error("On test: viable prefixes, error: " + err.Error())
+	} else if got, _ := m.Simulate("(S)("); !got {
+		t.Error("On test: viable prefixes, error: minimized DFA should accept (S)(")
+	}
+}
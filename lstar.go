@@ -0,0 +1,236 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"reflect"
+)
+
+// An Oracle is a minimally adequate teacher for an unknown regular language L. Member(w) reports whether w is in L.
+// Equivalent(d) reports whether L(d) = L, and if not, returns a counterexample: a string in exactly one of L(d) and L.
+type Oracle interface {
+	Member(w string) bool
+	Equivalent(d *DFA) (counterexample string, equivalent bool)
+}
+
+// DFAOracle is an Oracle which answers queries about the language of a known DFA. It is mostly useful for testing
+// learners offline, but also for checking two DFAs for equivalence.
+type DFAOracle struct {
+	Target *DFA
+}
+
+// Member reports whether the target DFA accepts w. Strings containing symbols outside the target's alphabet are
+// never members.
+func (o DFAOracle) Member(w string) bool {
+	ans, err := o.Target.Simulate(w)
+	return ans && err == nil
+}
+
+// Equivalent reports whether d recognizes the same language as the target DFA. If it does not, a shortest
+// (then lexicographically least) string on which the two DFAs disagree is returned. DFAs over different alphabets
+// are never equivalent; the counterexample is then a single symbol from the symmetric difference of the alphabets.
+func (o DFAOracle) Equivalent(d *DFA) (string, bool) {
+	if !d.alphabet.Equal(o.Target.alphabet) {
+		diff := sortedAlphabet(d.alphabet.SymmetricDifference(o.Target.alphabet))
+		return diff[0], false
+	}
+
+	//breadth-first search of the product automaton for a pair of states where
+	//exactly one accepts
+	symbols := sortedAlphabet(d.alphabet)
+	start := mapset.OrderedPair{First: o.Target.start, Second: d.start}
+	order := []mapset.OrderedPair{start}
+	access := map[mapset.OrderedPair]string{start: ""}
+	for i := 0; i < len(order); i++ {
+		st := order[i]
+		if o.Target.accept.Contains(st.First) != d.accept.Contains(st.Second) {
+			return access[st], false
+		}
+		for _, a := range symbols {
+			next := mapset.OrderedPair{First: o.Target.transition(st.First, a), Second: d.transition(st.Second, a)}
+			if _, seen := access[next]; !seen {
+				access[next] = access[st] + a
+				order = append(order, next)
+			}
+		}
+	}
+	return "", true
+}
+
+//observation table for L*. rows are indexed by prefixes in S and S·alphabet,
+//columns by suffixes in E. membership answers are cached by whole word, since
+//many (prefix, suffix) pairs concatenate to the same word.
+type observationTable struct {
+	oracle   Oracle
+	alphabet []string
+	prefixes []string
+	suffixes []string
+	answers  map[string]bool
+}
+
+func (t *observationTable) member(w string) bool {
+	ans, ok := t.answers[w]
+	if !ok {
+		ans = t.oracle.Member(w)
+		t.answers[w] = ans
+	}
+	return ans
+}
+
+//rows are compared by value, so they are encoded as strings of 0s and 1s
+func (t *observationTable) row(s string) string {
+	r := make([]byte, len(t.suffixes))
+	for i, e := range t.suffixes {
+		if t.member(s + e) {
+			r[i] = '1'
+		} else {
+			r[i] = '0'
+		}
+	}
+	return string(r)
+}
+
+func (t *observationTable) hasPrefix(s string) bool {
+	for _, p := range t.prefixes {
+		if p == s {
+			return true
+		}
+	}
+	return false
+}
+
+//returns a row of S·alphabet which matches no row of S, if there is one
+func (t *observationTable) unclosed() (string, bool) {
+	rows := map[string]bool{}
+	for _, s := range t.prefixes {
+		rows[t.row(s)] = true
+	}
+	for _, s := range t.prefixes {
+		for _, a := range t.alphabet {
+			if !rows[t.row(s+a)] {
+				return s + a, true
+			}
+		}
+	}
+	return "", false
+}
+
+//returns a suffix a+e separating two prefixes of S which have equal rows, if there is one
+func (t *observationTable) inconsistent() (string, bool) {
+	for i, s1 := range t.prefixes {
+		for _, s2 := range t.prefixes[i+1:] {
+			if t.row(s1) != t.row(s2) {
+				continue
+			}
+			for _, a := range t.alphabet {
+				for _, e := range t.suffixes {
+					if t.member(s1+a+e) != t.member(s2+a+e) {
+						return a + e, true
+					}
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+//builds the hypothesis DFA from a closed, consistent table. states are the
+//int indices of the distinct rows of S, in order of first appearance.
+func (t *observationTable) hypothesis() (*DFA, error) {
+	index := map[string]int{}
+	states := mapset.NewSet()
+	accept := mapset.NewSet()
+	for _, s := range t.prefixes {
+		r := t.row(s)
+		if _, seen := index[r]; seen {
+			continue
+		}
+		index[r] = len(index)
+		states.Add(index[r])
+		if t.member(s) {
+			accept.Add(index[r])
+		}
+	}
+
+	table := map[int]map[string]int{}
+	for _, s := range t.prefixes {
+		q := index[t.row(s)]
+		if _, done := table[q]; done {
+			continue
+		}
+		table[q] = map[string]int{}
+		for _, a := range t.alphabet {
+			table[q][a] = index[t.row(s+a)]
+		}
+	}
+	transition := func(state interface{}, input string) (nextState interface{}) {
+		return table[state.(int)][input]
+	}
+	alphabet := mapset.NewSet()
+	for _, a := range t.alphabet {
+		alphabet.Add(a)
+	}
+	return NewDFA(states, alphabet, transition, index[t.row("")], accept)
+}
+
+// LearnLStar infers a DFA for an unknown regular language over the given alphabet using Angluin's L* algorithm.
+// The learner asks the oracle membership and equivalence queries, and maintains an observation table which it
+// extends until it is closed and consistent before each equivalence query. The returned DFA is minimal for the
+// oracle's language, and its states are ints.
+//
+// Every symbol of the alphabet must be a single-character string, as with DFA.Simulate. An error is returned if
+// the oracle hands back a counterexample which the current hypothesis already classifies correctly, since the
+// learner could otherwise loop forever.
+func LearnLStar(alphabet mapset.Set, oracle Oracle) (*DFA, error) {
+	for _, elem := range alphabet.ToSlice() {
+		if reflect.TypeOf(elem).Kind() != reflect.String {
+			return nil, errors.New("gocompute/lstar: alphabet contains non-string type")
+		}
+		if len([]rune(elem.(string))) != 1 {
+			return nil, errors.New("gocompute/lstar: alphabet symbols must be single characters")
+		}
+	}
+	t := &observationTable{
+		oracle:   oracle,
+		alphabet: sortedAlphabet(alphabet),
+		prefixes: []string{""},
+		suffixes: []string{""},
+		answers:  map[string]bool{},
+	}
+
+	for {
+		for {
+			if s, ok := t.unclosed(); ok {
+				t.prefixes = append(t.prefixes, s)
+				continue
+			}
+			if e, ok := t.inconsistent(); ok {
+				t.suffixes = append(t.suffixes, e)
+				continue
+			}
+			break
+		}
+
+		d, err := t.hypothesis()
+		if err != nil {
+			return nil, errors.New("gocompute/lstar: invalid hypothesis: " + err.Error())
+		}
+		w, ok := oracle.Equivalent(d)
+		if ok {
+			return d, nil
+		}
+		if ans, err := d.Simulate(w); err != nil || ans == t.member(w) {
+			return nil, errors.New("gocompute/lstar: oracle returned invalid counterexample " + w)
+		}
+
+		//add every prefix of the counterexample to S
+		for i := range w {
+			if !t.hasPrefix(w[:i]) {
+				t.prefixes = append(t.prefixes, w[:i])
+			}
+		}
+		if !t.hasPrefix(w) {
+			t.prefixes = append(t.prefixes, w)
+		}
+	}
+}
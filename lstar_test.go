@@ -0,0 +1,86 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+	"time"
+)
+
+//counts membership queries, so tests can check the learner caches its answers
+type countingOracle struct {
+	DFAOracle
+	queries map[string]int
+}
+
+func (o countingOracle) Member(w string) bool {
+	o.queries[w]++
+	return o.DFAOracle.Member(w)
+}
+
+func TestLearnLStar(t *testing.T) {
+	for _, test := range nerodeTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		oracle := countingOracle{DFAOracle{test.d}, map[string]int{}}
+		d, err := LearnLStar(mapset.NewSet("0", "1"), oracle)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		if w, ok := oracle.Equivalent(d); !ok {
+			t.Error("On test: " + test.descriptor + ", error: learned DFA disagrees with target on string " + w)
+		}
+		if d.states.Cardinality() != test.classes {
+			t.Error("On test: " + test.descriptor + ", error: learned DFA should have " + strconv.Itoa(test.classes) + " states, got " + strconv.Itoa(d.states.Cardinality()))
+		}
+		for w, n := range oracle.queries {
+			if n > 1 {
+				t.Error("On test: " + test.descriptor + ", error: membership of " + w + " queried " + strconv.Itoa(n) + " times")
+			}
+		}
+	}
+}
+
+func TestDFAOracleEquivalent(t *testing.T) {
+	if w, ok := (DFAOracle{d1}).Equivalent(d3); !ok {
+		t.Error("On test: equivalent DFAs with different state types, error: reported counterexample " + w)
+	}
+	if w, ok := (DFAOracle{d1}).Equivalent(d5); ok || w != "" {
+		t.Error("On test: complementary DFAs, error: expected the empty string as counterexample, got " + w)
+	}
+	if w, ok := (DFAOracle{d4}).Equivalent(d5); ok || w != "0" {
+		t.Error("On test: odd 0s against odd 1s, error: expected counterexample 0, got " + w)
+	}
+}
+
+//an oracle which always claims the hypothesis is wrong, on a string it gets right
+type lyingOracle struct{}
+
+func (lyingOracle) Member(w string) bool             { return false }
+func (lyingOracle) Equivalent(d *DFA) (string, bool) { return "0", false }
+
+func TestLearnLStarInvalidCounterexample(t *testing.T) {
+	if _, err := LearnLStar(mapset.NewSet("0", "1"), lyingOracle{}); err == nil {
+		t.Error("On test: lying oracle, error: learner should reject a counterexample its hypothesis already handles")
+	}
+	if _, err := LearnLStar(mapset.NewSet("00", "1"), lyingOracle{}); err == nil {
+		t.Error("On test: multi-character alphabet symbol, error: learner should reject the alphabet")
+	}
+
+	//rejecting the alphabet should not leave it locked
+	alphabet := mapset.NewSet("00", "11", "22")
+	LearnLStar(alphabet, lyingOracle{})
+	done := make(chan bool)
+	go func() {
+		alphabet.Add("3")
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("On test: rejected alphabet, error: alphabet still locked after the learner returned")
+	}
+}
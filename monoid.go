@@ -0,0 +1,162 @@
+package gocompute
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// A Monoid is a finite monoid given by its multiplication table. Each element is represented by the shortest (then
+// lexicographically least) word whose action it is. Elements[0] is always the identity, represented by the empty word.
+// Table[i][j] is the index of the product of element i followed by element j.
+type Monoid struct {
+	Elements []string
+	Table    [][]int
+	//the transformation of the (indexed) states induced by each element, and the
+	//reverse lookup from an encoded transformation to its element
+	actions [][]int
+	index   map[string]int
+	symbols map[string][]int
+}
+
+//transformations are encoded as strings of comma-separated state indices so they can be map keys
+func encodeAction(action []int) string {
+	parts := make([]string, len(action))
+	for i, q := range action {
+		parts[i] = strconv.Itoa(q)
+	}
+	return strings.Join(parts, ",")
+}
+
+//returns the action of doing f and then g
+func composeActions(f, g []int) []int {
+	h := make([]int, len(f))
+	for i, q := range f {
+		h[i] = g[q]
+	}
+	return h
+}
+
+// Given a DFA d, d.TransitionMonoid() returns the monoid of transformations of the reachable states of d generated by
+// the actions of the alphabet symbols. Elements are found in breadth-first order, so their representative words are
+// in shortlex order. When d is minimal, this is the syntactic monoid of L(d).
+func (d DFA) TransitionMonoid() (*Monoid, error) {
+	ans, err := d.CheckDFA()
+	if ans == false && err != nil {
+		return nil, errors.New("gocompute/dfa: invalid DFA: " + err.Error())
+	}
+	states, _ := reachableStates(&d)
+	stateIndex := map[interface{}]int{}
+	for i, q := range states {
+		stateIndex[q] = i
+	}
+
+	m := &Monoid{index: map[string]int{}, symbols: map[string][]int{}}
+	alphabet := sortedAlphabet(d.alphabet)
+	for _, a := range alphabet {
+		action := make([]int, len(states))
+		for i, q := range states {
+			action[i] = stateIndex[d.transition(q, a)]
+		}
+		m.symbols[a] = action
+	}
+
+	identity := make([]int, len(states))
+	for i := range identity {
+		identity[i] = i
+	}
+	m.Elements = []string{""}
+	m.actions = [][]int{identity}
+	m.index[encodeAction(identity)] = 0
+	for i := 0; i < len(m.actions); i++ {
+		for _, a := range alphabet {
+			action := composeActions(m.actions[i], m.symbols[a])
+			key := encodeAction(action)
+			if _, seen := m.index[key]; !seen {
+				m.index[key] = len(m.actions)
+				m.Elements = append(m.Elements, m.Elements[i]+a)
+				m.actions = append(m.actions, action)
+			}
+		}
+	}
+
+	m.Table = make([][]int, len(m.actions))
+	for i, f := range m.actions {
+		m.Table[i] = make([]int, len(m.actions))
+		for j, g := range m.actions {
+			m.Table[i][j] = m.index[encodeAction(composeActions(f, g))]
+		}
+	}
+	return m, nil
+}
+
+// Given a DFA d, d.SyntacticMonoid() returns the syntactic monoid of L(d), the transition monoid of the minimal DFA
+// for L(d).
+func (d DFA) SyntacticMonoid() (*Monoid, error) {
+	minimal, err := d.Minimize()
+	if err != nil {
+		return nil, err
+	}
+	return minimal.TransitionMonoid()
+}
+
+// Element returns the index of the element of m whose action is that of the word w, or an error if w contains a
+// symbol outside the alphabet m was built from.
+func (m Monoid) Element(w string) (int, error) {
+	action := m.actions[0]
+	for _, r := range w {
+		symbol, ok := m.symbols[string(r)]
+		if !ok {
+			return 0, errors.New("gocompute/monoid: string " + w + " not in alphabet of monoid")
+		}
+		action = composeActions(action, symbol)
+	}
+	return m.index[encodeAction(action)], nil
+}
+
+// Idempotents returns the indices of the elements e of m with ee = e, in order.
+func (m Monoid) Idempotents() []int {
+	var idempotents []int
+	for e := range m.Elements {
+		if m.Table[e][e] == e {
+			idempotents = append(idempotents, e)
+		}
+	}
+	return idempotents
+}
+
+// IsCommutative reports whether xy = yx for all elements x and y of m.
+func (m Monoid) IsCommutative() bool {
+	for i := range m.Elements {
+		for j := i + 1; j < len(m.Elements); j++ {
+			if m.Table[i][j] != m.Table[j][i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsAperiodic reports whether m contains no non-trivial group, that is, whether for every element x there is an n
+// with x^n = x^(n+1). By Schutzenberger's theorem, a regular language is star-free exactly when its syntactic monoid
+// is aperiodic.
+func (m Monoid) IsAperiodic() bool {
+	for x := range m.Elements {
+		//follow the powers of x until one repeats. x is aperiodic iff the
+		//first repeat is of the immediately preceding power.
+		seen := map[int]bool{x: true}
+		power := x
+		for {
+			next := m.Table[power][x]
+			if next == power {
+				break
+			}
+			if seen[next] {
+				return false
+			}
+			seen[next] = true
+			power = next
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+)
+
+var d9, d9err = makeContainsOneOneStringStates()
+
+var monoidTests = []struct {
+	d           *DFA
+	err         error
+	elements    []string
+	idempotents int
+	commutative bool
+	aperiodic   bool
+	descriptor  string
+}{
+	{d1, d1err, []string{"", "1"}, 1, true, false, "DFA accepting strings with even number of 1s: the group Z2"},
+	{d7, d7err, []string{""}, 1, true, true, "DFA accepting all strings of 0s and 1s: the trivial monoid"},
+	{interd1, interd1err, []string{"", "0", "1", "01"}, 1, true, false, "Intersection of DFAs accepting even number of 1s and even number of 0s: the group Z2 x Z2"},
+	{d9, d9err, []string{"", "0", "1", "01", "10", "11"}, 5, false, true, "DFA accepting strings containing 11: star-free"},
+}
+
+func TestDFATransitionMonoid(t *testing.T) {
+	for _, test := range monoidTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		m, err := test.d.SyntacticMonoid()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		if len(m.Elements) != len(test.elements) {
+			t.Error("On test: " + test.descriptor + ", error: expected " + strconv.Itoa(len(test.elements)) + " elements, got " + strconv.Itoa(len(m.Elements)))
+			continue
+		}
+		for i, w := range test.elements {
+			if m.Elements[i] != w {
+				t.Error("On test: " + test.descriptor + ", error: element " + strconv.Itoa(i) + " should be represented by " + w + ", got " + m.Elements[i])
+			}
+		}
+		//the table must agree with concatenating representatives
+		for i, u := range m.Elements {
+			for j, v := range m.Elements {
+				k, _ := m.Element(u + v)
+				if m.Table[i][j] != k {
+					t.Error("On test: " + test.descriptor + ", error: product of " + u + " and " + v + " does not match the action of their concatenation")
+				}
+			}
+		}
+		if n := len(m.Idempotents()); n != test.idempotents {
+			t.Error("On test: " + test.descriptor + ", error: expected " + strconv.Itoa(test.idempotents) + " idempotents, got " + strconv.Itoa(n))
+		}
+		if m.IsCommutative() != test.commutative {
+			t.Error("On test: " + test.descriptor + ", error: commutativity should be " + strconv.FormatBool(test.commutative))
+		}
+		if m.IsAperiodic() != test.aperiodic {
+			t.Error("On test: " + test.descriptor + ", error: aperiodicity should be " + strconv.FormatBool(test.aperiodic))
+		}
+	}
+}
+
+func makeContainsOneOneStringStates() (*DFA, error) {
+	states := mapset.NewSet("q0", "q1", "q2")
+	alphabet := mapset.NewSet("0", "1")
+	transition := func(state interface{}, input string) (nextState interface{}) {
+		q0map := map[string]interface{}{"0": "q0", "1": "q1"}
+		q1map := map[string]interface{}{"0": "q0", "1": "q2"}
+		q2map := map[string]interface{}{"0": "q2", "1": "q2"}
+		fullmap := map[interface{}](map[string]interface{}){"q0": q0map, "q1": q1map, "q2": q2map}
+		return fullmap[state][input]
+	}
+	start := "q0"
+	accept := mapset.NewSet("q2")
+	return NewDFA(states, alphabet, transition, start, accept)
+}
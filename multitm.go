@@ -0,0 +1,405 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"strconv"
+	"strings"
+)
+
+// Internal representation of a multi-tape TM
+type MultiTapeTM struct {
+	tapes         int
+	states        mapset.Set
+	inputAlphabet mapset.Set
+	tapeAlphabet  mapset.Set
+	blank         string
+	transition    func(state string, symbols []string) (action MultiTapeAction)
+	start         string
+	accept        string
+	reject        string
+}
+
+// A MultiTapeAction is the value of a multi-tape TM's transition function: the state to enter, and for each tape the
+// symbol to write over the one read and the direction to move its head in.
+type MultiTapeAction struct {
+	State string
+	Write []string
+	Move  []TMMove
+}
+
+// A MultiTapeResult describes the end of a run of a multi-tape TM, as a TMResult does for a single tape, with the
+// contents and head position of each tape.
+type MultiTapeResult struct {
+	Outcome TMOutcome
+	State   string
+	Steps   int
+	Tapes   []string
+	Heads   []int
+}
+
+// Constructor method for creating a multi-tape TM, a deterministic Turing machine with k tapes, each with its own
+// head. The arguments are as for NewTM, with the number of tapes first, and a transition function which is called
+// with the k symbols under the heads and returns a MultiTapeAction writing and moving on every tape at once. Returns
+// a pointer to the newly created TM and an error, which is non-nil if the input was improperly formatted. The input
+// is written on the first tape, and the others start blank.
+func NewMultiTapeTM(tapes int,
+	states,
+	inputAlphabet,
+	tapeAlphabet mapset.Set,
+	blank string,
+	transition func(state string, symbols []string) (action MultiTapeAction),
+	start,
+	accept,
+	reject string) (*MultiTapeTM, error) {
+
+	m := &MultiTapeTM{tapes, states, inputAlphabet, tapeAlphabet, blank, transition, start, accept, reject}
+	ans, err := m.CheckMultiTapeTM()
+	if ans != true && err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+//returns every k-tuple of the given symbols, the last varying fastest
+func symbolTuples(symbols []string, k int) [][]string {
+	tuples := [][]string{{}}
+	for i := 0; i < k; i++ {
+		var longer [][]string
+		for _, t := range tuples {
+			for _, a := range symbols {
+				longer = append(longer, append(append([]string(nil), t...), a))
+			}
+		}
+		tuples = longer
+	}
+	return tuples
+}
+
+// Checks to make sure a given multi-tape TM m is properly formatted with correct input data.
+func (m MultiTapeTM) CheckMultiTapeTM() (bool, error) {
+	if m.tapes < 1 {
+		return false, errors.New("gocompute/tm: number of tapes must be positive")
+	}
+	//check the states and alphabets as for a single tape
+	single := TM{m.states, m.inputAlphabet, m.tapeAlphabet, m.blank, nil, m.start, m.accept, m.reject}
+	ans, err := single.checkStructure()
+	if ans != true && err != nil {
+		return false, err
+	}
+	halting := mapset.NewSet(m.accept, m.reject)
+
+	//check that for every state that does not halt and all symbols under the heads,
+	//the transition function enters a state, and writes a tape symbol and moves on every tape
+	tuples := symbolTuples(sortedAlphabet(m.tapeAlphabet), m.tapes)
+	for _, state := range sortedAlphabet(m.states) {
+		if halting.Contains(state) {
+			continue
+		}
+		for _, symbols := range tuples {
+			action := m.transition(state, symbols)
+			if !m.states.Contains(action.State) || len(action.Write) != m.tapes || len(action.Move) != m.tapes {
+				return false, errors.New("gocompute/tm: invalid transition function")
+			}
+			for i := range action.Write {
+				if !m.tapeAlphabet.Contains(action.Write[i]) {
+					return false, errors.New("gocompute/tm: invalid transition function")
+				}
+				if action.Move[i] < MoveLeft || action.Move[i] > MoveStay {
+					return false, errors.New("gocompute/tm: transition function returns an unknown move")
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+// Given a multi-tape TM m, a string w and a step bound, m.Run(w, maxSteps) runs m on w for at most maxSteps steps, as
+// TM.Run does, and returns the outcome along with the final state and tapes.
+func (m MultiTapeTM) Run(w string, maxSteps int) (*MultiTapeResult, error) {
+	ans, err := m.CheckMultiTapeTM()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/tm: invalid TM: " + err.Error())
+	}
+	for _, r := range w {
+		if !m.inputAlphabet.Contains(string(r)) {
+			return nil, errors.New("gocompute/tm: string to run on not in input alphabet of TM")
+		}
+	}
+	if maxSteps < 0 {
+		return nil, errors.New("gocompute/tm: step bound must not be negative")
+	}
+
+	tapes, heads := make([]*tmTape, m.tapes), make([]int, m.tapes)
+	tapes[0] = newTMTape(w, m.blank)
+	for i := 1; i < m.tapes; i++ {
+		tapes[i] = newTMTape("", m.blank)
+	}
+	state, steps := m.start, 0
+	symbols := make([]string, m.tapes)
+	for ; steps < maxSteps && state != m.accept && state != m.reject; steps++ {
+		for i, t := range tapes {
+			symbols[i] = t.read(heads[i])
+		}
+		action := m.transition(state, symbols)
+		for i, t := range tapes {
+			t.write(heads[i], action.Write[i])
+			switch action.Move[i] {
+			case MoveLeft:
+				heads[i]--
+			case MoveRight:
+				heads[i]++
+			}
+		}
+		state = action.State
+	}
+	result := &MultiTapeResult{Outcome: TMTimeout, State: state, Steps: steps}
+	switch state {
+	case m.accept:
+		result.Outcome = TMAccept
+	case m.reject:
+		result.Outcome = TMReject
+	}
+	for i, t := range tapes {
+		tape, head := t.contents(heads[i])
+		result.Tapes = append(result.Tapes, tape)
+		result.Heads = append(result.Heads, head)
+	}
+	return result, nil
+}
+
+//a state of the single-tape TM simulating a multi-tape TM. phase is one of init,
+//read, apply, right and back. state is the simulated state and symbols the
+//symbols under its heads, known for the tracks in found, where a mask has bit i
+//set for track i. done holds the tracks already updated, pending those whose heads
+//are to be marked on the next cell to the left, and right those to be marked on
+//the next cell to the right
+type trackControl struct {
+	phase   string
+	state   string
+	symbols string
+	found   int
+	done    int
+	pending int
+	right   int
+}
+
+//returns the name of a control state. the halting states are named accept and reject
+func (c trackControl) name() string {
+	if c.phase == "accept" || c.phase == "reject" {
+		return c.phase
+	}
+	return c.phase + "[" + strconv.Quote(c.state) + "," + strconv.Quote(c.symbols) + "," + strconv.Itoa(c.found) + "," +
+		strconv.Itoa(c.done) + "," + strconv.Itoa(c.pending) + "," + strconv.Itoa(c.right) + "]"
+}
+
+//a cell of the single tape: the symbol on each track, and which tracks have their head there
+type trackCell struct {
+	symbols []string
+	heads   int
+}
+
+//returns the private use character after r, moving on from the area in the Basic
+//Multilingual Plane to those in planes 15 and 16, or -1 once they have run out
+func nextPrivateUse(r rune) rune {
+	switch r {
+	case '\uF8FF':
+		return 0xF0000
+	case 0xFFFFD:
+		return 0x100000
+	case 0x10FFFD:
+		return -1
+	}
+	return r + 1
+}
+
+// Given a multi-tape TM m with k tapes, m.ToSingleTape() returns a pointer to a new single-tape TM recognizing the
+// same language, using the standard track encoding. Each cell of the single tape holds k tracks, and each track a
+// symbol of one of m's tapes together with a mark if that tape's head is there. Such a tuple is written as one fresh
+// character from the Unicode private use areas, taken from U+E000 to U+F8FF and then from the supplementary areas
+// starting at U+F0000 and U+100000, while the input and blank symbols stand for tuples with the symbol on the first
+// track, blanks on the others and no marks. An error is returned if m has too many tuples for the private use areas
+// to hold.
+//
+// The new TM first marks all heads on the first cell. To simulate each step of m it sweeps right from the leftmost
+// mark, collecting the symbols under the marks, then sweeps back left, updating each track and moving its mark, with
+// a short detour for marks moving right. A run of m taking t steps takes O(t^2) steps on the single tape; see
+// CompareWithSingleTape. The new TM has the accept and reject states accept and reject, and only its reachable
+// states are built.
+func (m MultiTapeTM) ToSingleTape() (*TM, error) {
+	ans, err := m.CheckMultiTapeTM()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/tm: invalid TM: " + err.Error())
+	}
+	k, all := m.tapes, 1<<uint(m.tapes)-1
+	symbols := sortedAlphabet(m.tapeAlphabet)
+
+	//encode every tuple of symbols and marks as a fresh character
+	encode := map[string]string{}
+	decode := map[string]trackCell{}
+	key := func(c trackCell) string {
+		return strings.Join(c.symbols, "") + "|" + strconv.Itoa(c.heads)
+	}
+	next := '\uE000'
+	for _, tuple := range symbolTuples(symbols, k) {
+		for heads := 0; heads <= all; heads++ {
+			for next >= 0 && m.tapeAlphabet.Contains(string(next)) {
+				next = nextPrivateUse(next)
+			}
+			if next < 0 {
+				return nil, errors.New("gocompute/tm: too many tuples of symbols and head marks to encode in the private use areas")
+			}
+			c := trackCell{tuple, heads}
+			encode[key(c)] = string(next)
+			decode[string(next)] = c
+			next = nextPrivateUse(next)
+		}
+	}
+	tapeAlphabet := m.tapeAlphabet.Clone()
+	for s := range decode {
+		tapeAlphabet.Add(s)
+	}
+	for _, a := range symbols {
+		tuple := []string{a}
+		for i := 1; i < k; i++ {
+			tuple = append(tuple, m.blank)
+		}
+		decode[a] = trackCell{tuple, 0}
+	}
+	write := func(c trackCell) string {
+		return encode[key(c)]
+	}
+
+	//what the single tape TM does in control state c reading cell
+	var step func(c trackControl, cell trackCell) (trackControl, trackCell, TMMove)
+	//updates the marked tracks of cell not yet done, then carries on to the next cell
+	apply := func(c trackControl, cell trackCell) (trackControl, trackCell, TMMove) {
+		read := strings.Split(c.symbols, "")
+		action := m.transition(c.state, read)
+		cell = trackCell{append([]string(nil), cell.symbols...), cell.heads | c.pending}
+		c.pending, c.right = 0, 0
+		for i := 0; i < k; i++ {
+			bit := 1 << uint(i)
+			if cell.heads&bit == 0 || c.done&bit != 0 {
+				continue
+			}
+			c.done |= bit
+			cell.symbols[i] = action.Write[i]
+			switch action.Move[i] {
+			case MoveLeft:
+				cell.heads &^= bit
+				c.pending |= bit
+			case MoveRight:
+				cell.heads &^= bit
+				c.right |= bit
+			}
+		}
+		if c.right != 0 {
+			c.phase = "right"
+			return c, cell, MoveRight
+		}
+		c.phase = "back"
+		next, _, move := step(c, cell)
+		return next, cell, move
+	}
+	step = func(c trackControl, cell trackCell) (trackControl, trackCell, TMMove) {
+		switch c.phase {
+		case "init":
+			cell.heads = all
+			c = trackControl{phase: "read", state: m.start}
+			if m.start == m.accept || m.start == m.reject {
+				c = trackControl{phase: "accept"}
+				if m.start == m.reject {
+					c.phase = "reject"
+				}
+			}
+			return c, cell, MoveStay
+		case "read":
+			read := strings.Split(c.symbols, "")
+			if c.symbols == "" {
+				read = make([]string, k)
+				for i := range read {
+					read[i] = m.blank
+				}
+			}
+			for i := 0; i < k; i++ {
+				if cell.heads&(1<<uint(i)) != 0 {
+					read[i] = cell.symbols[i]
+					c.found |= 1 << uint(i)
+				}
+			}
+			c.symbols = strings.Join(read, "")
+			if c.found != all {
+				return c, cell, MoveRight
+			}
+			return apply(trackControl{phase: "apply", state: c.state, symbols: c.symbols}, cell)
+		case "apply":
+			return apply(c, cell)
+		case "right":
+			cell.heads |= c.right
+			c.phase, c.right = "back", 0
+			return c, cell, MoveLeft
+		}
+		//back on the cell just updated, move on left, or start the next step if all is done
+		if c.done != all || c.pending != 0 {
+			c.phase = "apply"
+			return c, cell, MoveLeft
+		}
+		switch state := m.transition(c.state, strings.Split(c.symbols, "")).State; state {
+		case m.accept:
+			return trackControl{phase: "accept"}, cell, MoveStay
+		case m.reject:
+			return trackControl{phase: "reject"}, cell, MoveStay
+		default:
+			return trackControl{phase: "read", state: state}, cell, MoveStay
+		}
+	}
+
+	//build the reachable control states breadth-first
+	type entry struct {
+		state, symbol string
+	}
+	table := map[entry]TMAction{}
+	start := trackControl{phase: "init"}
+	order := []trackControl{start}
+	seen := map[string]bool{start.name(): true, "accept": true, "reject": true}
+	cells := sortedAlphabet(tapeAlphabet)
+	for i := 0; i < len(order); i++ {
+		c := order[i]
+		for _, s := range cells {
+			next, cell, move := step(c, decode[s])
+			table[entry{c.name(), s}] = TMAction{next.name(), write(cell), move}
+			if !seen[next.name()] {
+				seen[next.name()] = true
+				order = append(order, next)
+			}
+		}
+	}
+	states := mapset.NewSet()
+	for name := range seen {
+		states.Add(name)
+	}
+	transition := func(state, symbol string) TMAction {
+		return table[entry{state, symbol}]
+	}
+	return NewTM(states, m.inputAlphabet, tapeAlphabet, m.blank, transition, start.name(), "accept", "reject")
+}
+
+// Given a multi-tape TM m, a string w and a step bound, m.CompareWithSingleTape(w, maxSteps) runs both m and
+// m.ToSingleTape() on w for at most maxSteps steps each, and returns both results, so that their outcomes and the
+// number of steps each took can be compared.
+func (m MultiTapeTM) CompareWithSingleTape(w string, maxSteps int) (*MultiTapeResult, *TMResult, error) {
+	multi, err := m.Run(w, maxSteps)
+	if err != nil {
+		return nil, nil, err
+	}
+	single, err := m.ToSingleTape()
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := single.Run(w, maxSteps)
+	if err != nil {
+		return nil, nil, err
+	}
+	return multi, result, nil
+}
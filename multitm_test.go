@@ -0,0 +1,171 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+)
+
+func makePalindromeMultiTapeTM() (*MultiTapeTM, error) {
+	//copy copies the input to the second tape, rewind takes the first head back to
+	//the start, and compare reads the first tape forwards and the second backwards
+	transition := func(state string, symbols []string) MultiTapeAction {
+		a, b := symbols[0], symbols[1]
+		switch {
+		case state == "copy" && a != "_":
+			return MultiTapeAction{"copy", []string{a, a}, []TMMove{MoveRight, MoveRight}}
+		case state == "copy":
+			return MultiTapeAction{"rewind", []string{a, b}, []TMMove{MoveLeft, MoveLeft}}
+		case state == "rewind" && a != "_":
+			return MultiTapeAction{"rewind", []string{a, b}, []TMMove{MoveLeft, MoveStay}}
+		case state == "rewind":
+			return MultiTapeAction{"compare", []string{a, b}, []TMMove{MoveRight, MoveStay}}
+		case state == "compare" && a == "_":
+			return MultiTapeAction{"accept", []string{a, b}, []TMMove{MoveStay, MoveStay}}
+		case state == "compare" && a == b:
+			return MultiTapeAction{"compare", []string{a, b}, []TMMove{MoveRight, MoveLeft}}
+		}
+		return MultiTapeAction{"reject", []string{a, b}, []TMMove{MoveStay, MoveStay}}
+	}
+	return NewMultiTapeTM(2, mapset.NewSet("copy", "rewind", "compare", "accept", "reject"), mapset.NewSet("a", "b"), mapset.NewSet("a", "b", "_"), "_", transition, "copy", "accept", "reject")
+}
+
+func makeShiftMultiTapeTM() (*MultiTapeTM, error) {
+	//writes x on three tapes, moving their heads left, right and not at all, then
+	//accepts once the first tape's head is back on the input
+	transition := func(state string, symbols []string) MultiTapeAction {
+		switch {
+		case state == "q0":
+			return MultiTapeAction{"q1", []string{"x", "x", "x"}, []TMMove{MoveLeft, MoveRight, MoveStay}}
+		case state == "q1" && symbols[0] == "_":
+			return MultiTapeAction{"q1", symbols, []TMMove{MoveRight, MoveRight, MoveStay}}
+		}
+		return MultiTapeAction{"accept", symbols, []TMMove{MoveStay, MoveStay, MoveStay}}
+	}
+	return NewMultiTapeTM(3, mapset.NewSet("q0", "q1", "accept", "reject"), mapset.NewSet("a"), mapset.NewSet("a", "x", "_"), "_", transition, "q0", "accept", "reject")
+}
+
+func isPalindrome(w string) bool {
+	r := []rune(w)
+	for i := range r {
+		if r[i] != r[len(r)-1-i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMultiTapeTMRun(t *testing.T) {
+	m, err := makePalindromeMultiTapeTM()
+	if err != nil {
+		t.Error("On test: multi-tape TM deciding palindromes, error: " + err.Error())
+		t.FailNow()
+	}
+	result, err := m.Run("abba", 100)
+	if err != nil {
+		t.Error("On test: multi-tape TM deciding palindromes, error: " + err.Error())
+		t.FailNow()
+	}
+	if result.Outcome != TMAccept || result.Steps != 15 || result.Tapes[0] != "abba_" || result.Tapes[1] != "_abba" ||
+		result.Heads[0] != 4 || result.Heads[1] != 0 {
+		t.Error("On test: multi-tape TM deciding palindromes, error: wrong result on abba")
+	}
+	for _, w := range allStrings([]string{"a", "b"}, 6) {
+		result, _ := m.Run(w, 100)
+		if (result.Outcome == TMAccept) != isPalindrome(w) {
+			t.Error("On test: multi-tape TM deciding palindromes, error: wrong outcome on " + w)
+		}
+	}
+}
+
+func TestMultiTapeToSingleTape(t *testing.T) {
+	m1, err1 := makePalindromeMultiTapeTM()
+	m2, err2 := makeShiftMultiTapeTM()
+	var tests = []struct {
+		m          *MultiTapeTM
+		err        error
+		alphabet   []string
+		descriptor string
+	}{
+		{m1, err1, []string{"a", "b"}, "multi-tape TM deciding palindromes"},
+		{m2, err2, []string{"a"}, "multi-tape TM moving its heads apart"},
+	}
+	for _, test := range tests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			continue
+		}
+		single, err := test.m.ToSingleTape()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		for _, w := range allStrings(test.alphabet, 5) {
+			multiResult, _ := test.m.Run(w, 100000)
+			singleResult, err := single.Run(w, 100000)
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+				break
+			}
+			if multiResult.Outcome != singleResult.Outcome {
+				t.Error("On test: " + test.descriptor + ", error: single tape TM answered " + singleResult.Outcome.String() + " instead of " + multiResult.Outcome.String() + " on " + w)
+			}
+			if singleResult.Steps < multiResult.Steps {
+				t.Error("On test: " + test.descriptor + ", error: single tape TM took " + strconv.Itoa(singleResult.Steps) + " steps on " + w + ", fewer than " + strconv.Itoa(multiResult.Steps))
+			}
+		}
+	}
+	multi, single, err := m1.CompareWithSingleTape("abba", 100000)
+	if err != nil || multi.Steps != 15 || single.Outcome != TMAccept || single.Steps <= multi.Steps {
+		t.Error("On test: step comparison on abba, error: wrong results")
+	}
+	if _, _, err := m1.CompareWithSingleTape("abc", 10); err == nil {
+		t.Error("On test: comparison on a string outside the input alphabet, error: should have been reported")
+	}
+}
+
+func TestCheckMultiTapeTM(t *testing.T) {
+	wrongLength := func(state string, symbols []string) MultiTapeAction {
+		return MultiTapeAction{"accept", symbols[:1], []TMMove{MoveStay}}
+	}
+	states := mapset.NewSet("q0", "accept", "reject")
+	if _, err := NewMultiTapeTM(2, states, mapset.NewSet("a"), mapset.NewSet("a", "_"), "_", wrongLength, "q0", "accept", "reject"); err == nil {
+		t.Error("On test: action for one tape in a two-tape TM, error: invalid TM should have been reported")
+	}
+	if _, err := NewMultiTapeTM(0, states, mapset.NewSet("a"), mapset.NewSet("a", "_"), "_", wrongLength, "q0", "accept", "reject"); err == nil {
+		t.Error("On test: TM with no tapes, error: invalid TM should have been reported")
+	}
+}
+
+func TestMultiTapeToSingleTapeManyTuples(t *testing.T) {
+	var steps = []struct {
+		r, next rune
+	}{
+		{'\uE000', '\uE001'},
+		{'\uF8FF', 0xF0000},
+		{0xFFFFD, 0x100000},
+		{0x10FFFD, -1},
+	}
+	for _, step := range steps {
+		if got := nextPrivateUse(step.r); got != step.next {
+			t.Error("On test: private use character after " + strconv.QuoteRune(step.r) + ", error: got " + strconv.Itoa(int(got)))
+		}
+	}
+
+	//3^8 tuples of symbols with 2^8 sets of marks do not fit in the private use areas
+	transition := func(state string, symbols []string) MultiTapeAction {
+		moves := make([]TMMove, len(symbols))
+		for i := range moves {
+			moves[i] = MoveStay
+		}
+		return MultiTapeAction{"accept", symbols, moves}
+	}
+	m, err := NewMultiTapeTM(8, mapset.NewSet("q0", "accept", "reject"), mapset.NewSet("a", "b"), mapset.NewSet("a", "b", "_"), "_", transition, "q0", "accept", "reject")
+	if err != nil {
+		t.Error("On test: eight-tape TM, error: " + err.Error())
+		t.FailNow()
+	}
+	if _, err := m.ToSingleTape(); err == nil {
+		t.Error("On test: eight-tape TM, error: running out of private use characters should have been reported")
+	}
+}
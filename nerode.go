@@ -0,0 +1,180 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+)
+
+// A NerodeClass is one block of the Myhill-Nerode partition of a DFA's reachable states. All states in a class
+// accept exactly the same set of suffixes. Access is the shortest (then lexicographically least) word taking the DFA
+// from its start state into the class.
+type NerodeClass struct {
+	States mapset.Set
+	Access string
+}
+
+// A NerodePartition is the result of d.NerodeClasses(). Suffixes[i][j] holds a shortest word distinguishing
+// Classes[i] from Classes[j], that is, a word accepted from one class and rejected from the other. The diagonal
+// entries are empty.
+type NerodePartition struct {
+	Classes  []NerodeClass
+	Suffixes [][]string
+}
+
+//the distinguishability table works on a list of states with an index for each,
+//and stores for every unordered pair of distinct states a shortest distinguishing
+//suffix, if one exists
+type suffixTable struct {
+	index    map[interface{}]int
+	suffixes map[[2]int]string
+}
+
+func (t suffixTable) lookup(p, q interface{}) (string, bool) {
+	i, j := t.index[p], t.index[q]
+	if i > j {
+		i, j = j, i
+	}
+	s, ok := t.suffixes[[2]int{i, j}]
+	return s, ok
+}
+
+//table filling algorithm, run one suffix length at a time so that every recorded
+//suffix is as short as possible. a pair is distinguished by a word of length k
+//iff for some symbol a the successor pair is distinguished by a word of length k-1.
+func distinguish(d *DFA, states []interface{}) suffixTable {
+	symbols := sortedAlphabet(d.alphabet)
+	t := suffixTable{map[interface{}]int{}, map[[2]int]string{}}
+	for i, state := range states {
+		t.index[state] = i
+	}
+
+	frontier := map[[2]int]bool{}
+	for i := range states {
+		for j := i + 1; j < len(states); j++ {
+			if d.accept.Contains(states[i]) != d.accept.Contains(states[j]) {
+				t.suffixes[[2]int{i, j}] = ""
+				frontier[[2]int{i, j}] = true
+			}
+		}
+	}
+
+	for len(frontier) > 0 {
+		next := map[[2]int]bool{}
+		for i := range states {
+			for j := i + 1; j < len(states); j++ {
+				if _, done := t.suffixes[[2]int{i, j}]; done {
+					continue
+				}
+				for _, a := range symbols {
+					p, q := t.index[d.transition(states[i], a)], t.index[d.transition(states[j], a)]
+					if p > q {
+						p, q = q, p
+					}
+					if frontier[[2]int{p, q}] {
+						t.suffixes[[2]int{i, j}] = a + t.suffixes[[2]int{p, q}]
+						next[[2]int{i, j}] = true
+						break
+					}
+				}
+			}
+		}
+		frontier = next
+	}
+	return t
+}
+
+// Given a DFA d, d.NerodeClasses() returns the partition of the reachable states of d into Myhill-Nerode equivalence
+// classes. Classes are ordered by their access words, shortest first, and each pair of classes comes with a shortest
+// distinguishing suffix. The number of classes is the number of states of the minimal DFA for L(d).
+func (d DFA) NerodeClasses() (*NerodePartition, error) {
+	ans, err := d.CheckDFA()
+	if ans == false && err != nil {
+		return nil, errors.New("gocompute/dfa: invalid DFA: " + err.Error())
+	}
+	states, access := reachableStates(&d)
+	t := distinguish(&d, states)
+
+	//states are visited in breadth-first order, so the first state of each class
+	//carries the class's shortest access word
+	var reps []interface{}
+	p := &NerodePartition{}
+	for _, state := range states {
+		found := false
+		for c, rep := range reps {
+			if _, ok := t.lookup(state, rep); !ok {
+				p.Classes[c].States.Add(state)
+				found = true
+				break
+			}
+		}
+		if !found {
+			reps = append(reps, state)
+			p.Classes = append(p.Classes, NerodeClass{mapset.NewSet(state), access[state]})
+		}
+	}
+
+	p.Suffixes = make([][]string, len(reps))
+	for i := range reps {
+		p.Suffixes[i] = make([]string, len(reps))
+		for j := range reps {
+			if i != j {
+				p.Suffixes[i][j], _ = t.lookup(reps[i], reps[j])
+			}
+		}
+	}
+	return p, nil
+}
+
+// Given a DFA d and two of its states p and q, d.DistinguishingSuffix(p, q) returns a shortest word w such that
+// exactly one of p and q leads to an accept state on w, and true. If p and q are equivalent, it returns false. The
+// states need not be reachable, so this can be used to explain why two states of a Union or Intersection product differ.
+func (d DFA) DistinguishingSuffix(p, q interface{}) (string, bool, error) {
+	ans, err := d.CheckDFA()
+	if ans == false && err != nil {
+		return "", false, errors.New("gocompute/dfa: invalid DFA: " + err.Error())
+	}
+	if !d.states.Contains(p) || !d.states.Contains(q) {
+		return "", false, errors.New("gocompute/dfa: state not in set of states")
+	}
+	if p == q {
+		return "", false, nil
+	}
+	t := distinguish(&d, d.states.ToSlice())
+	w, ok := t.lookup(p, q)
+	return w, ok, nil
+}
+
+// Given a DFA d recognizing L(d), d.Minimize() returns a pointer to a new DFA recognizing L(d) with the fewest
+// possible states. Each state of the new DFA is the int index of the corresponding class in d.NerodeClasses().
+func (d DFA) Minimize() (*DFA, error) {
+	p, err := d.NerodeClasses()
+	if err != nil {
+		return nil, err
+	}
+	classOf := map[interface{}]int{}
+	states := mapset.NewSet()
+	accept := mapset.NewSet()
+	for c, class := range p.Classes {
+		states.Add(c)
+		for _, state := range class.States.ToSlice() {
+			classOf[state] = c
+			if d.accept.Contains(state) {
+				accept.Add(c)
+			}
+		}
+	}
+
+	//the transition table is built eagerly, so the new DFA does not keep d alive
+	table := map[int]map[string]int{}
+	for c, class := range p.Classes {
+		rep := class.States.ToSlice()[0]
+		table[c] = map[string]int{}
+		for _, a := range sortedAlphabet(d.alphabet) {
+			table[c][a] = classOf[d.transition(rep, a)]
+		}
+	}
+	transition := func(state interface{}, input string) (nextState interface{}) {
+		return table[state.(int)][input]
+	}
+	return NewDFA(states, d.alphabet, transition, 0, accept)
+}
@@ -0,0 +1,114 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+)
+
+var uniond1d1, uniond1d1err = d1.Union(d1) // four product states, only two of them reachable
+
+var nerodeTests = []struct {
+	d          *DFA
+	err        error
+	classes    int
+	access     []string
+	descriptor string
+}{
+	{d1, d1err, 2, []string{"", "1"}, "DFA accepting strings with even number of 1s"},
+	{d4, d4err, 2, []string{"", "0"}, "DFA accepting strings with odd number of 0s"},
+	{d7, d7err, 1, []string{""}, "DFA accepting all strings of 0s and 1s"},
+	{d8, d8err, 1, []string{""}, "DFA accepting no strings, with an unreachable accept state"},
+	{uniond1, uniond1err, 4, []string{"", "0", "1", "01"}, "Union of DFAs accepting even number of 1s or even number of 0s"},
+	{uniond1d1, uniond1d1err, 2, []string{"", "1"}, "Union of a DFA with itself"},
+	{compld1, compld1err, 2, []string{"", "1"}, "Complement of a DFA accepting even number of 1s"},
+}
+
+func TestDFANerodeClasses(t *testing.T) {
+	for _, test := range nerodeTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		p, err := test.d.NerodeClasses()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		if len(p.Classes) != test.classes {
+			t.Error("On test: " + test.descriptor + ", error: expected " + strconv.Itoa(test.classes) + " classes, got " + strconv.Itoa(len(p.Classes)))
+			continue
+		}
+		for i, class := range p.Classes {
+			if class.Access != test.access[i] {
+				t.Error("On test: " + test.descriptor + ", error: class " + strconv.Itoa(i) + " should have access word " + test.access[i] + ", got " + class.Access)
+			}
+		}
+		//a distinguishing suffix must be accepted after exactly one of the two access words
+		for i := range p.Classes {
+			for j := range p.Classes {
+				if i == j {
+					continue
+				}
+				s := p.Suffixes[i][j]
+				a, _ := test.d.Simulate(p.Classes[i].Access + s)
+				b, _ := test.d.Simulate(p.Classes[j].Access + s)
+				if a == b {
+					t.Error("On test: " + test.descriptor + ", error: suffix " + s + " does not distinguish classes " + strconv.Itoa(i) + " and " + strconv.Itoa(j))
+				}
+			}
+		}
+	}
+}
+
+func TestDFADistinguishingSuffix(t *testing.T) {
+	if uniond1err != nil {
+		t.Error("On test: distinguishing suffix, error: " + uniond1err.Error())
+		t.FailNow()
+	}
+	p := mapset.OrderedPair{First: "q0", Second: "q1"}
+	q := mapset.OrderedPair{First: "q1", Second: "q0"}
+	w, ok, err := uniond1.DistinguishingSuffix(p, q)
+	if err != nil {
+		t.Error("On test: distinguishing suffix, error: " + err.Error())
+		t.FailNow()
+	}
+	if !ok || w != "0" {
+		t.Error("On test: distinguishing suffix, error: expected suffix 0 between product states, got " + w)
+	}
+	_, ok, err = d8.DistinguishingSuffix("q0", "q1")
+	if err != nil || !ok {
+		t.Error("On test: distinguishing suffix, error: unreachable accept state should be distinguishable from a reject state")
+	}
+	_, ok, err = d7.DistinguishingSuffix("q0", "q0")
+	if err != nil || ok {
+		t.Error("On test: distinguishing suffix, error: a state should not be distinguishable from itself")
+	}
+}
+
+func TestDFAMinimize(t *testing.T) {
+	for _, test := range nerodeTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		m, err := test.d.Minimize()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		if m.states.Cardinality() != test.classes {
+			t.Error("On test: " + test.descriptor + ", error: minimal DFA should have " + strconv.Itoa(test.classes) + " states")
+		}
+		for _, w := range []string{"", "0", "1", "0011", "0001011011", "100", "001001011001001011"} {
+			want, _ := test.d.Simulate(w)
+			got, err := m.Simulate(w)
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", while testing string " + w + ", error: " + err.Error())
+			}
+			if got != want {
+				t.Error("On test: " + test.descriptor + ", error: minimal DFA should have answered " + strconv.FormatBool(want) + " to string " + w)
+			}
+		}
+	}
+}
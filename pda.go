@@ -1,40 +1,371 @@
 package gocompute
 
 import (
+	"errors"
 	"github.com/jophish/golang-set"
+	"reflect"
+	"strconv"
 )
+
 // Internal representation of a PDA
 type PDA struct {
-	states     *mapset.Set
-	slphabet   *mapset.Set
-	transition func(state, input, stackSymbol string) (nextStates *mapset.Set)
-	start      string
-	stackStart string
-	accept     *mapset.Set
-}
-
-// Constructor for a new PDA.
-func newPDA(states,
-	alphabet *mapset.Set,
-	transition func(state, input, stackSymbol string) (nextStates *mapset.Set),
+	states        mapset.Set
+	alphabet      mapset.Set
+	stackAlphabet mapset.Set
+	transition    func(state, input, stackSymbol string) (moves mapset.Set)
+	start         string
+	stackStart    string
+	accept        mapset.Set
+	acceptance    PDAAcceptance
+}
+
+// PDAAcceptance selects how a PDA accepts its input.
+type PDAAcceptance int
+
+const (
+	// AcceptFinalState accepts when all input has been read and the PDA is in an accept state.
+	AcceptFinalState PDAAcceptance = iota
+	// AcceptEmptyStack accepts when all input has been read and the stack is empty. The set of accept states is
+	// ignored.
+	AcceptEmptyStack
+	// AcceptFinalStateAndEmptyStack accepts when all input has been read, the PDA is in an accept state and the stack
+	// is empty.
+	AcceptFinalStateAndEmptyStack
+)
+
+// A PDAMove is an element of the set returned by a PDA's transition function. Taking the move enters State and
+// pushes Push onto the stack in place of the symbol read, if any, so that the first symbol of Push ends up on top.
+// An empty Push pops the stack.
+type PDAMove struct {
+	State string
+	Push  string
+}
+
+// Default bounds used by PDA.Simulate. See PDA.SimulateBounded.
+const (
+	DefaultPDAStackBound = 1000
+	DefaultPDAStepBound  = 100000
+)
+
+// Constructor method for creating a PDA. Takes as input a set of string states, a set of strings representing the
+// input alphabet, a set of strings representing the stack alphabet, a transition function, a start state, an initial
+// stack symbol and a set of accept states. Returns a pointer to the newly created PDA and an error, which is non-nil
+// if the input was improperly formatted. The PDA accepts by final state; see NewPDAWithAcceptance for other
+// conventions.
+//
+// Input and stack symbols are single-character strings. The transition function is called with a state, an input
+// symbol or "" for a move which reads no input, and a stack symbol or "" for a move which leaves the stack alone. It
+// returns the set of PDAMoves possible from there, or nil if there are none. Every move must enter a valid state and
+// push only symbols from the stack alphabet.
+func NewPDA(states,
+	alphabet,
+	stackAlphabet mapset.Set,
+	transition func(state, input, stackSymbol string) (moves mapset.Set),
+	start,
+	stackStart string,
+	accept mapset.Set) (*PDA, error) {
+
+	return NewPDAWithAcceptance(states, alphabet, stackAlphabet, transition, start, stackStart, accept, AcceptFinalState)
+}
+
+// Constructor method for creating a PDA which accepts according to the given convention. The arguments are otherwise
+// as for NewPDA.
+func NewPDAWithAcceptance(states,
+	alphabet,
+	stackAlphabet mapset.Set,
+	transition func(state, input, stackSymbol string) (moves mapset.Set),
 	start,
 	stackStart string,
-	accept *mapset.Set) *PDA {
+	accept mapset.Set,
+	acceptance PDAAcceptance) (*PDA, error) {
 
-	return &PDA{states, alphabet, transition, start, stackStart, accept}
+	p := &PDA{states, alphabet, stackAlphabet, transition, start, stackStart, accept, acceptance}
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
-// Simulates a PDA. Unimplemented.
-func (p PDA) Simulate(w string) bool {
-	//each configuration (state, input, stack head) is a node in a graph, with directed edges
-	//going out as specified by the transition function. do a BFS, and return TRUE if any nodes
-	//reachable from the initial configuration are ones representing configs containing an
-	//accept state. if no such state is found, return FALSE
-	/*	currentState := p.start
-		stackHead := p.stackStart
-		for _, r := range w {
-			currentState = p.transition(currentState, string(r), stackHead)
+//checks that every element of a set is a single-character string
+func checkSymbols(symbols mapset.Set) bool {
+	for _, elem := range symbols.ToSlice() {
+		if reflect.TypeOf(elem).Kind() != reflect.String || len([]rune(elem.(string))) != 1 {
+			return false
 		}
-	*/
+	}
 	return true
 }
+
+// Checks to make sure a given PDA p is properly formatted with correct input data.
+func (p PDA) CheckPDA() (bool, error) {
+	//check that all states are strings
+	for _, elem := range p.states.ToSlice() {
+		if reflect.TypeOf(elem).Kind() != reflect.String {
+			return false, errors.New("gocompute/pda: set of states contains non-string type")
+		}
+	}
+
+	//check that all input and stack symbols are single-character strings
+	if !checkSymbols(p.alphabet) {
+		return false, errors.New("gocompute/pda: alphabet contains a symbol which is not a single-character string")
+	}
+	if !checkSymbols(p.stackAlphabet) {
+		return false, errors.New("gocompute/pda: stack alphabet contains a symbol which is not a single-character string")
+	}
+
+	//check that start state and initial stack symbol are valid
+	if !p.states.Contains(p.start) {
+		return false, errors.New("gocompute/pda: start state not in set of states")
+	}
+	if !p.stackAlphabet.Contains(p.stackStart) {
+		return false, errors.New("gocompute/pda: initial stack symbol not in stack alphabet")
+	}
+
+	//check that set of accept states is subset of states
+	if !p.states.IsSuperset(p.accept) {
+		return false, errors.New("gocompute/pda: set of accept states not a subset of set of all states")
+	}
+
+	if p.acceptance < AcceptFinalState || p.acceptance > AcceptFinalStateAndEmptyStack {
+		return false, errors.New("gocompute/pda: unknown acceptance mode")
+	}
+
+	//check that every move from every state, on every input symbol or ε and every
+	//stack symbol or ε, enters a state and pushes only stack symbols
+	inputs := append([]string{""}, sortedAlphabet(p.alphabet)...)
+	tops := append([]string{""}, sortedAlphabet(p.stackAlphabet)...)
+	for _, state := range p.states.ToSlice() {
+		for _, a := range inputs {
+			for _, top := range tops {
+				moves := p.transition(state.(string), a, top)
+				if moves == nil {
+					continue
+				}
+				for _, elem := range moves.ToSlice() {
+					move, ok := elem.(PDAMove)
+					if !ok || !p.states.Contains(move.State) {
+						return false, errors.New("gocompute/pda: invalid transition function")
+					}
+					for _, r := range move.Push {
+						if !p.stackAlphabet.Contains(string(r)) {
+							return false, errors.New("gocompute/pda: transition pushes symbol not in stack alphabet")
+						}
+					}
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+//a configuration of a PDA: the current state, the number of input symbols read,
+//and the stack contents with the top symbol first
+type pdaConfig struct {
+	state string
+	pos   int
+	stack string
+}
+
+//reports whether a configuration which has read all of the input is accepting
+func (p PDA) accepts(c pdaConfig) bool {
+	switch p.acceptance {
+	case AcceptEmptyStack:
+		return c.stack == ""
+	case AcceptFinalStateAndEmptyStack:
+		return c.stack == "" && p.accept.Contains(c.state)
+	}
+	return p.accept.Contains(c.state)
+}
+
+// Given a PDA p and a string w, p.Simulate(w) simulates p on w and returns true if some computation of p reads all
+// of w and ends in an accepting configuration, as defined by the PDA's acceptance mode. It is shorthand for p.SimulateBounded(w, DefaultPDAStackBound,
+// DefaultPDAStepBound).
+func (p PDA) Simulate(w string) (bool, error) {
+	return p.SimulateBounded(w, DefaultPDAStackBound, DefaultPDAStepBound)
+}
+
+// Given a PDA p and a string w, p.SimulateBounded(w, maxStack, maxSteps) explores the configurations (state, input
+// position, stack) reachable from the initial configuration breadth-first, and returns true as soon as it finds one
+// which has read all of w and accepts.
+//
+// At each configuration the transition function is consulted for every combination of reading the next input symbol
+// or "" (an ε-move on the input), and popping the top stack symbol or "" (leaving the stack alone). Configurations
+// already seen are not explored again, so ε-loops that return to the same configuration terminate. Configurations
+// whose stack would grow beyond maxStack symbols are dropped, and the search stops after expanding maxSteps
+// configurations. If either bound cuts the search short before an accepting configuration is found, the answer is
+// inconclusive and an error is returned along with false.
+func (p PDA) SimulateBounded(w string, maxStack, maxSteps int) (bool, error) {
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return false, errors.New("gocompute/pda: invalid PDA: " + err.Error())
+	}
+
+	input := []rune(w)
+	for _, r := range input {
+		if !p.alphabet.Contains(string(r)) {
+			return false, errors.New("gocompute/pda: string to test not in alphabet of PDA")
+		}
+	}
+
+	initial := pdaConfig{p.start, 0, p.stackStart}
+	queue := []pdaConfig{initial}
+	seen := map[pdaConfig]bool{initial: true}
+	truncated := false
+	for steps := 0; len(queue) > 0; steps++ {
+		if steps >= maxSteps {
+			return false, errors.New("gocompute/pda: step bound of " + strconv.Itoa(maxSteps) + " exceeded")
+		}
+		c := queue[0]
+		queue = queue[1:]
+		if c.pos == len(input) && p.accepts(c) {
+			return true, nil
+		}
+
+		inputs := []string{""}
+		if c.pos < len(input) {
+			inputs = append(inputs, string(input[c.pos]))
+		}
+		tops := []string{""}
+		if c.stack != "" {
+			tops = append(tops, string([]rune(c.stack)[0]))
+		}
+		for _, a := range inputs {
+			for _, top := range tops {
+				moves := p.transition(c.state, a, top)
+				if moves == nil {
+					continue
+				}
+				for elem := range moves.Iter() {
+					move := elem.(PDAMove)
+					next := pdaConfig{move.State, c.pos + len([]rune(a)), move.Push + c.stack[len(top):]}
+					if len([]rune(next.stack)) > maxStack {
+						truncated = true
+						continue
+					}
+					if !seen[next] {
+						seen[next] = true
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+	}
+	if truncated {
+		return false, errors.New("gocompute/pda: stack bound of " + strconv.Itoa(maxStack) + " exceeded")
+	}
+	return false, nil
+}
+
+//returns base, or base followed by enough primes to make it a name not in states
+func freshState(states mapset.Set, base string) string {
+	for states.Contains(base) {
+		base += "'"
+	}
+	return base
+}
+
+//returns a single-character stack symbol not in the stack alphabet, to mark the bottom of the stack
+func freshStackSymbol(stackAlphabet mapset.Set) string {
+	for r := '⊥'; ; r++ {
+		if !stackAlphabet.Contains(string(r)) {
+			return string(r)
+		}
+	}
+}
+
+// Given a PDA p, p.ToEmptyStackAcceptance() returns a pointer to a new PDA which accepts by empty stack and recognizes
+// the same language as p. The new PDA starts by pushing a fresh bottom-of-stack marker under p's initial stack symbol,
+// so that it cannot empty its stack by accident, then runs p. Whenever p could accept, it may instead move to a new
+// state which pops everything, marker included.
+func (p PDA) ToEmptyStackAcceptance() (*PDA, error) {
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/pda: invalid PDA: " + err.Error())
+	}
+	if p.acceptance == AcceptEmptyStack {
+		q := p
+		return &q, nil
+	}
+
+	bottom := freshStackSymbol(p.stackAlphabet)
+	start := freshState(p.states, "start")
+	drain := freshState(p.states.Union(mapset.NewSet(start)), "drain")
+	acceptance := p.acceptance
+	transition := func(state, input, stackSymbol string) (moves mapset.Set) {
+		moves = mapset.NewSet()
+		switch state {
+		case start:
+			if input == "" && stackSymbol == bottom {
+				moves.Add(PDAMove{p.start, p.stackStart + bottom})
+			}
+			return moves
+		case drain:
+			if input == "" && stackSymbol != "" {
+				moves.Add(PDAMove{drain, ""})
+			}
+			return moves
+		}
+		//the marker is invisible to p, which would see an empty stack
+		if stackSymbol != bottom {
+			if original := p.transition(state, input, stackSymbol); original != nil {
+				moves = moves.Union(original)
+			}
+		}
+		if input == "" && p.accept.Contains(state) {
+			if (acceptance == AcceptFinalState && stackSymbol != "") || stackSymbol == bottom {
+				moves.Add(PDAMove{drain, ""})
+			}
+		}
+		return moves
+	}
+	states := p.states.Union(mapset.NewSet(start, drain))
+	stackAlphabet := p.stackAlphabet.Union(mapset.NewSet(bottom))
+	return NewPDAWithAcceptance(states, p.alphabet, stackAlphabet, transition, start, bottom, mapset.NewSet(), AcceptEmptyStack)
+}
+
+// Given a PDA p, p.ToFinalStateAcceptance() returns a pointer to a new PDA which accepts by final state and
+// recognizes the same language as p. The new PDA starts by pushing a fresh bottom-of-stack marker under p's initial
+// stack symbol, then runs p. Whenever the marker is exposed, p's stack is empty, and if p could accept there the new
+// PDA may move to its single accept state.
+func (p PDA) ToFinalStateAcceptance() (*PDA, error) {
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/pda: invalid PDA: " + err.Error())
+	}
+	if p.acceptance == AcceptFinalState {
+		q := p
+		return &q, nil
+	}
+
+	bottom := freshStackSymbol(p.stackAlphabet)
+	start := freshState(p.states, "start")
+	final := freshState(p.states.Union(mapset.NewSet(start)), "final")
+	acceptance := p.acceptance
+	transition := func(state, input, stackSymbol string) (moves mapset.Set) {
+		moves = mapset.NewSet()
+		switch state {
+		case start:
+			if input == "" && stackSymbol == bottom {
+				moves.Add(PDAMove{p.start, p.stackStart + bottom})
+			}
+			return moves
+		case final:
+			return moves
+		}
+		if stackSymbol != bottom {
+			if original := p.transition(state, input, stackSymbol); original != nil {
+				moves = moves.Union(original)
+			}
+		}
+		if input == "" && stackSymbol == bottom {
+			if acceptance == AcceptEmptyStack || p.accept.Contains(state) {
+				moves.Add(PDAMove{final, bottom})
+			}
+		}
+		return moves
+	}
+	states := p.states.Union(mapset.NewSet(start, final))
+	stackAlphabet := p.stackAlphabet.Union(mapset.NewSet(bottom))
+	return NewPDA(states, p.alphabet, stackAlphabet, transition, start, bottom, mapset.NewSet(final))
+}
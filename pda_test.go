@@ -0,0 +1,239 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+)
+
+var p1, p1err = makeAnBnPDA()
+var p2, p2err = makeEvenPalindromePDA()
+var p3, p3err = makeEpsilonLoopPDA()
+
+var pdaSimulateTests = []struct {
+	p           *PDA
+	err         error
+	testStrings map[string]bool
+	descriptor  string
+}{
+	{p1, p1err, map[string]bool{"": true, "ab": true, "aabb": true, "aaabbb": true, "a": false, "abb": false, "aab": false, "ba": false, "abab": false}, "PDA accepting a^n b^n"},
+	{p2, p2err, map[string]bool{"": true, "aa": true, "abba": true, "babbab": true, "ab": false, "aba": false, "abab": false}, "PDA accepting even-length palindromes, nondeterministically guessing the middle"},
+	{p3, p3err, map[string]bool{"": true, "a": true, "aa": false}, "PDA with an ε-loop on the input that does not change its configuration"},
+	{p4, p4err, map[string]bool{"": true, "ab": true, "aabb": true, "a": false, "abb": false, "ba": false, "abab": false}, "PDA accepting a^n b^n by empty stack"},
+	{p5, p5err, map[string]bool{"": true, "ab": true, "aabb": true, "a": false, "abb": false, "ba": false, "abab": false}, "PDA accepting a^n b^n by final state and empty stack"},
+}
+
+func TestPDASimulate(t *testing.T) {
+	for _, test := range pdaSimulateTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		for k, v := range test.testStrings {
+			ans, err := test.p.Simulate(k)
+			if ans != v {
+				t.Error("On test: " + test.descriptor + ", error: PDA should have answered " + strconv.FormatBool(v) + " to string " + k)
+			}
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", while testing string " + k + ", error: " + err.Error())
+			}
+		}
+	}
+}
+
+func TestPDASimulateBounds(t *testing.T) {
+	p, err := makePushForeverPDA()
+	if err != nil {
+		t.Error("On test: PDA pushing forever on ε-moves, error: " + err.Error())
+		t.FailNow()
+	}
+	if _, err := p.SimulateBounded("b", 50, 1000000); err == nil {
+		t.Error("On test: PDA pushing forever on ε-moves, error: stack bound should have been reported")
+	}
+	if _, err := p.SimulateBounded("b", 1000000, 50); err == nil {
+		t.Error("On test: PDA pushing forever on ε-moves, error: step bound should have been reported")
+	}
+	if ans, err := p.SimulateBounded("a", 50, 1000000); !ans || err != nil {
+		t.Error("On test: PDA pushing forever on ε-moves, error: should accept a before any bound is reached")
+	}
+}
+
+func pdaMoves(moves ...PDAMove) mapset.Set {
+	s := mapset.NewSet()
+	for _, m := range moves {
+		s.Add(m)
+	}
+	return s
+}
+
+func makeAnBnPDA() (*PDA, error) {
+	//q0 reads a's pushing A, q1 reads b's popping A, and q2 accepts once the bottom marker Z is back on top
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "q0" && input == "a" && stackSymbol != "":
+			return pdaMoves(PDAMove{"q0", "A" + stackSymbol})
+		case state == "q0" && input == "" && stackSymbol != "":
+			return pdaMoves(PDAMove{"q1", stackSymbol})
+		case state == "q1" && input == "b" && stackSymbol == "A":
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q1" && input == "" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q2", "Z"})
+		}
+		return nil
+	}
+	return NewPDA(mapset.NewSet("q0", "q1", "q2"), mapset.NewSet("a", "b"), mapset.NewSet("Z", "A"), transition, "q0", "Z", mapset.NewSet("q2"))
+}
+
+func makeEvenPalindromePDA() (*PDA, error) {
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "q0" && input != "" && stackSymbol != "":
+			return pdaMoves(PDAMove{"q0", input + stackSymbol})
+		case state == "q0" && input == "" && stackSymbol != "":
+			return pdaMoves(PDAMove{"q1", stackSymbol})
+		case state == "q1" && input != "" && stackSymbol == input:
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q1" && input == "" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q2", "Z"})
+		}
+		return nil
+	}
+	return NewPDA(mapset.NewSet("q0", "q1", "q2"), mapset.NewSet("a", "b"), mapset.NewSet("Z", "a", "b"), transition, "q0", "Z", mapset.NewSet("q2"))
+}
+
+func makeEpsilonLoopPDA() (*PDA, error) {
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "q0" && input == "" && stackSymbol == "":
+			return pdaMoves(PDAMove{"q0", ""})
+		case state == "q0" && input == "a" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q1", "Z"})
+		}
+		return nil
+	}
+	return NewPDA(mapset.NewSet("q0", "q1"), mapset.NewSet("a"), mapset.NewSet("Z"), transition, "q0", "Z", mapset.NewSet("q0", "q1"))
+}
+
+func makePushForeverPDA() (*PDA, error) {
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "q0" && input == "" && stackSymbol == "":
+			return pdaMoves(PDAMove{"q0", "X"})
+		case state == "q0" && input == "a" && stackSymbol == "":
+			return pdaMoves(PDAMove{"q1", ""})
+		}
+		return nil
+	}
+	return NewPDA(mapset.NewSet("q0", "q1"), mapset.NewSet("a", "b"), mapset.NewSet("Z", "X"), transition, "q0", "Z", mapset.NewSet("q1"))
+}
+
+func TestCheckPDA(t *testing.T) {
+	valid := func(state, input, stackSymbol string) mapset.Set {
+		return pdaMoves(PDAMove{"q0", stackSymbol})
+	}
+	pushesUnknown := func(state, input, stackSymbol string) mapset.Set {
+		return pdaMoves(PDAMove{"q0", "Y"})
+	}
+	entersUnknown := func(state, input, stackSymbol string) mapset.Set {
+		return pdaMoves(PDAMove{"q9", ""})
+	}
+	tests := []struct {
+		states, alphabet, stackAlphabet mapset.Set
+		transition                      func(state, input, stackSymbol string) mapset.Set
+		start, stackStart               string
+		accept                          mapset.Set
+		ok                              bool
+		descriptor                      string
+	}{
+		{mapset.NewSet("q0"), mapset.NewSet("a"), mapset.NewSet("Z"), valid, "q0", "Z", mapset.NewSet("q0"), true, "valid PDA"},
+		{mapset.NewSet("q0", 1), mapset.NewSet("a"), mapset.NewSet("Z"), valid, "q0", "Z", mapset.NewSet("q0"), false, "non-string state"},
+		{mapset.NewSet("q0"), mapset.NewSet("ab"), mapset.NewSet("Z"), valid, "q0", "Z", mapset.NewSet("q0"), false, "multi-character input symbol"},
+		{mapset.NewSet("q0"), mapset.NewSet("a"), mapset.NewSet("Z"), valid, "q1", "Z", mapset.NewSet("q0"), false, "start state not in states"},
+		{mapset.NewSet("q0"), mapset.NewSet("a"), mapset.NewSet("Z"), valid, "q0", "Y", mapset.NewSet("q0"), false, "initial stack symbol not in stack alphabet"},
+		{mapset.NewSet("q0"), mapset.NewSet("a"), mapset.NewSet("Z"), valid, "q0", "Z", mapset.NewSet("q1"), false, "accept states not subset of states"},
+		{mapset.NewSet("q0"), mapset.NewSet("a"), mapset.NewSet("Z"), pushesUnknown, "q0", "Z", mapset.NewSet("q0"), false, "transition pushes unknown stack symbol"},
+		{mapset.NewSet("q0"), mapset.NewSet("a"), mapset.NewSet("Z"), entersUnknown, "q0", "Z", mapset.NewSet("q0"), false, "transition enters unknown state"},
+	}
+	for _, test := range tests {
+		_, err := NewPDA(test.states, test.alphabet, test.stackAlphabet, test.transition, test.start, test.stackStart, test.accept)
+		if (err == nil) != test.ok {
+			t.Error("On test: " + test.descriptor + ", error: constructor should have returned ok = " + strconv.FormatBool(test.ok))
+		}
+	}
+}
+
+var pdaAcceptanceTests = []struct {
+	p           *PDA
+	err         error
+	testStrings []string
+	descriptor  string
+}{
+	{p1, p1err, []string{"", "ab", "aabb", "a", "abb", "ba", "abab"}, "PDA accepting a^n b^n by final state"},
+	{p2, p2err, []string{"", "aa", "abba", "ab", "aba", "abab"}, "PDA accepting even-length palindromes by final state"},
+	{p4, p4err, []string{"", "ab", "aabb", "a", "abb", "ba", "abab"}, "PDA accepting a^n b^n by empty stack"},
+	{p5, p5err, []string{"", "ab", "aabb", "a", "abb", "ba", "abab", "aab"}, "PDA accepting a^n b^n by final state and empty stack"},
+}
+
+var p4, p4err = makeAnBnEmptyStackPDA(AcceptEmptyStack)
+var p5, p5err = makeAnBnEmptyStackPDA(AcceptFinalStateAndEmptyStack)
+
+func TestPDAAcceptanceConversions(t *testing.T) {
+	for _, test := range pdaAcceptanceTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		empty, err := test.p.ToEmptyStackAcceptance()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		final, err := test.p.ToFinalStateAcceptance()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		//converting there and back again must also preserve the language
+		roundTrip, err := empty.ToFinalStateAcceptance()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			t.FailNow()
+		}
+		for _, w := range test.testStrings {
+			want, err := test.p.Simulate(w)
+			if err != nil {
+				t.Error("On test: " + test.descriptor + ", while testing string " + w + ", error: " + err.Error())
+			}
+			for _, converted := range []*PDA{empty, final, roundTrip} {
+				got, err := converted.Simulate(w)
+				if err != nil {
+					t.Error("On test: " + test.descriptor + ", while testing string " + w + ", error: " + err.Error())
+				}
+				if got != want {
+					t.Error("On test: " + test.descriptor + ", error: converted PDA should have answered " + strconv.FormatBool(want) + " to string " + w)
+				}
+			}
+		}
+	}
+}
+
+func makeAnBnEmptyStackPDA(acceptance PDAAcceptance) (*PDA, error) {
+	//pushes an A for each a and pops one for each b. the initial Z is popped
+	//when switching to b's, so the stack empties exactly after a^n b^n.
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case state == "q0" && input == "a" && stackSymbol != "":
+			return pdaMoves(PDAMove{"q0", "A" + stackSymbol})
+		case state == "q0" && input == "" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q0" && input == "b" && stackSymbol == "A":
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q1" && input == "b" && stackSymbol == "A":
+			return pdaMoves(PDAMove{"q1", ""})
+		case state == "q1" && input == "" && stackSymbol == "Z":
+			return pdaMoves(PDAMove{"q1", ""})
+		}
+		return nil
+	}
+	return NewPDAWithAcceptance(mapset.NewSet("q0", "q1"), mapset.NewSet("a", "b"), mapset.NewSet("Z", "A"), transition, "q0", "Z", mapset.NewSet("q1"), acceptance)
+}
@@ -0,0 +1,413 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A PAutomaton represents a regular set of configurations of a PDA. It is a nondeterministic finite automaton over
+// the stack alphabet of the PDA whose states include the states of the PDA, and it contains the configuration with
+// state q and stack w if it can read w, top symbol first, from q to an accept state.
+type PAutomaton struct {
+	control       mapset.Set
+	stackAlphabet mapset.Set
+	states        mapset.Set
+	transitions   map[string]map[string]map[string]bool
+	accept        mapset.Set
+}
+
+//returns an empty P-automaton for p with the given states
+func newPAutomaton(p *PDA, states mapset.Set) *PAutomaton {
+	return &PAutomaton{p.states, p.stackAlphabet, states, map[string]map[string]map[string]bool{}, mapset.NewSet()}
+}
+
+//adds a transition from one state to another on a stack symbol, or "" for an
+//ε-transition, and reports whether it is new
+func (a *PAutomaton) add(from, symbol, to string) bool {
+	if a.transitions[from] == nil {
+		a.transitions[from] = map[string]map[string]bool{}
+	}
+	if a.transitions[from][symbol] == nil {
+		a.transitions[from][symbol] = map[string]bool{}
+	}
+	if a.transitions[from][symbol][to] {
+		return false
+	}
+	a.transitions[from][symbol][to] = true
+	return true
+}
+
+//returns the states a reaches from the given states on a stack symbol, without ε-transitions
+func (a *PAutomaton) step(from map[string]bool, symbol string) map[string]bool {
+	to := map[string]bool{}
+	for q := range from {
+		for r := range a.transitions[q][symbol] {
+			to[r] = true
+		}
+	}
+	return to
+}
+
+// Constructor method for creating a P-automaton, representing a set of configurations of a PDA p. Takes as input p, a
+// set of string states including the states of p, a transition function mapping a state and a stack symbol to the set
+// of states it may move to, or nil if there are none, and a set of accept states. Returns a pointer to the newly
+// created P-automaton and an error, which is non-nil if the input was improperly formatted.
+func NewPAutomaton(p *PDA,
+	states mapset.Set,
+	transition func(state, stackSymbol string) (nextStates mapset.Set),
+	accept mapset.Set) (*PAutomaton, error) {
+
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/pda: invalid PDA: " + err.Error())
+	}
+	for _, elem := range states.ToSlice() {
+		if reflect.TypeOf(elem).Kind() != reflect.String {
+			return nil, errors.New("gocompute/pda: set of states contains non-string type")
+		}
+	}
+	if !states.IsSuperset(p.states) {
+		return nil, errors.New("gocompute/pda: states of P-automaton must include the states of the PDA")
+	}
+	if !states.IsSuperset(accept) {
+		return nil, errors.New("gocompute/pda: set of accept states not a subset of set of all states")
+	}
+	a := newPAutomaton(p, states)
+	a.accept = accept
+	for _, q := range sortedAlphabet(states) {
+		for _, x := range sortedAlphabet(p.stackAlphabet) {
+			next := transition(q, x)
+			if next == nil {
+				continue
+			}
+			for _, r := range next.ToSlice() {
+				if !states.Contains(r) {
+					return nil, errors.New("gocompute/pda: invalid transition function")
+				}
+				a.add(q, x, r.(string))
+			}
+		}
+	}
+	return a, nil
+}
+
+// Given a PDA p and a map from states of p to DFAs over its stack alphabet, NewPAutomatonFromDFAs(p, stacks) returns a
+// pointer to a new P-automaton containing the configurations with state q and a stack accepted by stacks[q], and none
+// with a state missing from the map. The states of the DFA for q are copied as states [q,0], [q,1], ... in
+// breadth-first order, with primes added to avoid the states of p, and q itself moves like the start state.
+func NewPAutomatonFromDFAs(p *PDA, stacks map[string]*DFA) (*PAutomaton, error) {
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/pda: invalid PDA: " + err.Error())
+	}
+	a := newPAutomaton(p, p.states.Clone())
+	symbols := sortedAlphabet(p.stackAlphabet)
+	for _, q := range sortedAlphabet(p.states) {
+		d, ok := stacks[q]
+		if !ok {
+			continue
+		}
+		ans, err := d.CheckDFA()
+		if ans == false && err != nil {
+			return nil, errors.New("gocompute/pda: invalid DFA for state " + q + ": " + err.Error())
+		}
+		if !d.alphabet.Equal(p.stackAlphabet) {
+			return nil, errors.New("gocompute/pda: alphabet of DFA for state " + q + " must be the stack alphabet of the PDA")
+		}
+		order, _ := reachableStates(d)
+		names := map[interface{}]string{}
+		for i, s := range order {
+			names[s] = freshState(a.states, "["+q+","+strconv.Itoa(i)+"]")
+			a.states.Add(names[s])
+			if d.accept.Contains(s) {
+				a.accept.Add(names[s])
+			}
+		}
+		for _, s := range order {
+			for _, x := range symbols {
+				a.add(names[s], x, names[d.transition(s, x)])
+			}
+		}
+		for _, x := range symbols {
+			a.add(q, x, names[d.transition(d.start, x)])
+		}
+		if d.accept.Contains(d.start) {
+			a.accept.Add(q)
+		}
+	}
+	return a, nil
+}
+
+// Given a P-automaton a, a.Contains(state, stack) returns true if a contains the configuration of its PDA with the
+// given state and stack, written top symbol first.
+func (a PAutomaton) Contains(state, stack string) (bool, error) {
+	if !a.control.Contains(state) {
+		return false, errors.New("gocompute/pda: state not in set of states of PDA")
+	}
+	current := map[string]bool{state: true}
+	for _, r := range stack {
+		if !a.stackAlphabet.Contains(string(r)) {
+			return false, errors.New("gocompute/pda: stack not in stack alphabet of PDA")
+		}
+		current = a.step(current, string(r))
+	}
+	for q := range current {
+		if a.accept.Contains(q) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Given a P-automaton a and a state q of its PDA, a.StackDFA(q) returns a pointer to a new DFA over the stack alphabet
+// accepting the stacks, written top symbol first, which a contains together with q. It is built by the subset
+// construction, and its states are the sets of states of a reached, as sorted comma-separated strings.
+func (a PAutomaton) StackDFA(q string) (*DFA, error) {
+	if !a.control.Contains(q) {
+		return nil, errors.New("gocompute/pda: state not in set of states of PDA")
+	}
+	key := func(set map[string]bool) string {
+		names := make([]string, 0, len(set))
+		for s := range set {
+			names = append(names, s)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+	symbols := sortedAlphabet(a.stackAlphabet)
+	start := map[string]bool{q: true}
+	sets := map[string]map[string]bool{key(start): start}
+	order := []string{key(start)}
+	table := map[string]map[string]string{}
+	for i := 0; i < len(order); i++ {
+		table[order[i]] = map[string]string{}
+		for _, x := range symbols {
+			next := a.step(sets[order[i]], x)
+			k := key(next)
+			if _, ok := sets[k]; !ok {
+				sets[k] = next
+				order = append(order, k)
+			}
+			table[order[i]][x] = k
+		}
+	}
+	states, accept := mapset.NewSet(), mapset.NewSet()
+	for _, k := range order {
+		states.Add(k)
+		for s := range sets[k] {
+			if a.accept.Contains(s) {
+				accept.Add(k)
+				break
+			}
+		}
+	}
+	transition := func(state interface{}, input string) interface{} {
+		return table[state.(string)][input]
+	}
+	return NewDFA(states, a.stackAlphabet, transition, key(start), accept)
+}
+
+//a rule of a PDA for saturation: from state with top symbol on the stack, it may
+//move to next replacing the top with push, top first. input is ignored
+type pdaRule struct {
+	state, top, next string
+	push             []string
+}
+
+//returns the rules of p, with moves which leave the stack alone turned into one
+//rule for each stack symbol and the bottom marker, pushing the symbol back
+func (p PDA) rules(bottom string) []pdaRule {
+	var rules []pdaRule
+	inputs := append([]string{""}, sortedAlphabet(p.alphabet)...)
+	tops := append([]string{""}, sortedAlphabet(p.stackAlphabet)...)
+	split := func(s string) []string {
+		var symbols []string
+		for _, r := range s {
+			symbols = append(symbols, string(r))
+		}
+		return symbols
+	}
+	seen := map[string]bool{}
+	for _, q := range sortedAlphabet(p.states) {
+		for _, a := range inputs {
+			for _, top := range tops {
+				moves := p.transition(q, a, top)
+				if moves == nil {
+					continue
+				}
+				for _, elem := range moves.ToSlice() {
+					m := elem.(PDAMove)
+					if top != "" {
+						rules = append(rules, pdaRule{q, top, m.State, split(m.Push)})
+						continue
+					}
+					for _, x := range append(sortedAlphabet(p.stackAlphabet), bottom) {
+						rules = append(rules, pdaRule{q, x, m.State, split(m.Push + x)})
+					}
+				}
+			}
+		}
+	}
+	//different inputs give the same rule, which only needs to be applied once
+	unique := rules[:0]
+	for _, r := range rules {
+		k := strconv.Quote(r.state) + strconv.Quote(r.top) + strconv.Quote(r.next) + strconv.Quote(strings.Join(r.push, ""))
+		if !seen[k] {
+			seen[k] = true
+			unique = append(unique, r)
+		}
+	}
+	return unique
+}
+
+//returns a copy of c over the stack alphabet with a bottom marker, which contains
+//(q, w⊥) whenever c contains (q, w), and in which no transition enters a state
+//of the PDA, as both saturation algorithms assume. the accept state is returned too
+func (c *PAutomaton) withBottom(bottom string) (*PAutomaton, string) {
+	a := &PAutomaton{c.control, c.stackAlphabet.Union(mapset.NewSet(bottom)), c.states.Clone(), map[string]map[string]map[string]bool{}, mapset.NewSet()}
+	final := freshState(a.states, "final")
+	a.states.Add(final)
+	a.accept.Add(final)
+	//a transition into a state q of the PDA enters a copy of q instead
+	copies := map[string]string{}
+	for _, q := range sortedAlphabet(c.control) {
+		copies[q] = freshState(a.states, "["+q+"]")
+		a.states.Add(copies[q])
+	}
+	target := func(r string) string {
+		if copy, ok := copies[r]; ok {
+			return copy
+		}
+		return r
+	}
+	for _, q := range sortedAlphabet(c.states) {
+		for x, to := range c.transitions[q] {
+			for r := range to {
+				a.add(q, x, target(r))
+				if copy, ok := copies[q]; ok {
+					a.add(copy, x, target(r))
+				}
+			}
+		}
+		if c.accept.Contains(q) {
+			a.add(q, bottom, final)
+			if copy, ok := copies[q]; ok {
+				a.add(copy, bottom, final)
+			}
+		}
+	}
+	return a, final
+}
+
+//returns the P-automaton over the stack alphabet of c which contains (q, w) when a
+//contains (q, w⊥), removing the ε-transitions of a, which only leave states of the PDA
+func (c *PAutomaton) withoutBottom(a *PAutomaton, bottom, final string) *PAutomaton {
+	r := &PAutomaton{c.control, c.stackAlphabet, a.states.Clone(), map[string]map[string]map[string]bool{}, mapset.NewSet()}
+	r.states.Remove(final)
+	for _, q := range sortedAlphabet(a.states) {
+		//the states q stands for, through its ε-transitions
+		via := map[string]bool{q: true}
+		for s := range a.transitions[q][""] {
+			via[s] = true
+		}
+		for s := range via {
+			for x, to := range a.transitions[s] {
+				for t := range to {
+					switch {
+					case x == bottom && t == final:
+						r.accept.Add(q)
+					case x != "" && x != bottom:
+						r.add(q, x, t)
+					}
+				}
+			}
+		}
+	}
+	return r
+}
+
+// Given a PDA p and a P-automaton c for p, p.PostStar(c) returns a pointer to a new P-automaton containing every
+// configuration p can reach from a configuration in c, including those in c, ignoring the input. It adds
+// transitions to a copy of c until nothing changes: whenever c reaches t from q reading the top symbol X, and p can
+// move from q with X on top to q' replacing X with w, a path from q' to t reading w is added, through new states
+// when w has more than one symbol, or an ε-transition when w is empty.
+//
+// The new P-automaton may have extra states, named [q,X,i] after the rules of p which created them, with primes
+// added to avoid the existing states.
+func (p PDA) PostStar(c *PAutomaton) (*PAutomaton, error) {
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/pda: invalid PDA: " + err.Error())
+	}
+	if !c.control.Equal(p.states) || !c.stackAlphabet.Equal(p.stackAlphabet) {
+		return nil, errors.New("gocompute/pda: P-automaton is not for this PDA")
+	}
+	bottom := freshStackSymbol(p.stackAlphabet)
+	a, final := c.withBottom(bottom)
+	rules := p.rules(bottom)
+	//the states along the path each rule pushing more than one symbol adds
+	paths := make([][]string, len(rules))
+	for i, r := range rules {
+		for j := 1; j < len(r.push); j++ {
+			s := freshState(a.states, "["+r.next+","+r.push[0]+","+strconv.Itoa(j)+"]")
+			a.states.Add(s)
+			paths[i] = append(paths[i], s)
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for i, r := range rules {
+			//the states reached from r.state reading r.top, possibly after an ε-transition
+			from := map[string]bool{r.state: true}
+			for s := range a.transitions[r.state][""] {
+				from[s] = true
+			}
+			for t := range a.step(from, r.top) {
+				if len(r.push) == 0 {
+					changed = a.add(r.next, "", t) || changed
+					continue
+				}
+				path := append(append([]string{r.next}, paths[i]...), t)
+				for j, x := range r.push {
+					changed = a.add(path[j], x, path[j+1]) || changed
+				}
+			}
+		}
+	}
+	return c.withoutBottom(a, bottom, final), nil
+}
+
+// Given a PDA p and a P-automaton c for p, p.PreStar(c) returns a pointer to a new P-automaton containing every
+// configuration from which p can reach a configuration in c, including those in c, ignoring the input. It adds
+// transitions to a copy of c until nothing changes: whenever p can move from q with X on top to q' replacing X with
+// w, and c reaches t from q' reading w, a transition from q to t reading X is added. No states are added other than
+// a copy of each state of p with transitions entering it in c, which the algorithm needs to keep apart.
+func (p PDA) PreStar(c *PAutomaton) (*PAutomaton, error) {
+	ans, err := p.CheckPDA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/pda: invalid PDA: " + err.Error())
+	}
+	if !c.control.Equal(p.states) || !c.stackAlphabet.Equal(p.stackAlphabet) {
+		return nil, errors.New("gocompute/pda: P-automaton is not for this PDA")
+	}
+	bottom := freshStackSymbol(p.stackAlphabet)
+	a, final := c.withBottom(bottom)
+	rules := p.rules(bottom)
+	for changed := true; changed; {
+		changed = false
+		for _, r := range rules {
+			reached := map[string]bool{r.next: true}
+			for _, x := range r.push {
+				reached = a.step(reached, x)
+			}
+			for t := range reached {
+				changed = a.add(r.state, r.top, t) || changed
+			}
+		}
+	}
+	return c.withoutBottom(a, bottom, final), nil
+}
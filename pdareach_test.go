@@ -0,0 +1,178 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"testing"
+)
+
+//returns the configurations of p reachable from the given ones, as state and
+//stack pairs, without exploring stacks longer than maxStack
+func reachableConfigs(p *PDA, from []pdaConfig, maxStack int) map[pdaConfig]bool {
+	inputs := append([]string{""}, sortedAlphabet(p.alphabet)...)
+	seen := map[pdaConfig]bool{}
+	queue := []pdaConfig{}
+	for _, c := range from {
+		seen[c] = true
+		queue = append(queue, c)
+	}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		tops := []string{""}
+		if c.stack != "" {
+			tops = append(tops, string([]rune(c.stack)[0]))
+		}
+		for _, a := range inputs {
+			for _, top := range tops {
+				moves := p.transition(c.state, a, top)
+				if moves == nil {
+					continue
+				}
+				for elem := range moves.Iter() {
+					move := elem.(PDAMove)
+					next := pdaConfig{move.State, 0, move.Push + c.stack[len(top):]}
+					if len([]rune(next.stack)) <= maxStack && !seen[next] {
+						seen[next] = true
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+	}
+	return seen
+}
+
+//returns a P-automaton for p containing just the configuration with state q and stack w
+func singleConfig(p *PDA, q, w string) (*PAutomaton, error) {
+	states := p.states.Clone()
+	names := []string{q}
+	for i := range []rune(w) {
+		names = append(names, freshState(states, "s"+string(rune('0'+i))))
+		states.Add(names[i+1])
+	}
+	symbols := []rune(w)
+	transition := func(state, stackSymbol string) mapset.Set {
+		for i := range symbols {
+			if state == names[i] && stackSymbol == string(symbols[i]) {
+				return mapset.NewSet(names[i+1])
+			}
+		}
+		return nil
+	}
+	return NewPAutomaton(p, states, transition, mapset.NewSet(names[len(names)-1]))
+}
+
+var pdaReachTests = []struct {
+	make       func() (*PDA, error)
+	state      string
+	stack      string
+	descriptor string
+}{
+	{makeAnBnPDA, "q0", "Z", "PDA accepting a^n b^n"},
+	{makeAnBnPDA, "q2", "Z", "PDA accepting a^n b^n, from its accept state"},
+	{makeEvenPalindromePDA, "q1", "abZ", "PDA accepting even-length palindromes"},
+	{makePushForeverPDA, "q0", "Z", "PDA pushing forever on ε-moves which leave the stack alone"},
+	{makeEpsilonLoopPDA, "q0", "", "PDA with ε-moves on an empty stack"},
+}
+
+func TestPostStarPreStar(t *testing.T) {
+	for _, test := range pdaReachTests {
+		p, err := test.make()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		c, err := singleConfig(p, test.state, test.stack)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		post, err := p.PostStar(c)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		pre, err := p.PreStar(c)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		target := pdaConfig{test.state, 0, test.stack}
+		forward := reachableConfigs(p, []pdaConfig{target}, 8)
+		for _, q := range sortedAlphabet(p.states) {
+			for _, w := range allStrings(sortedAlphabet(p.stackAlphabet), 4) {
+				config := pdaConfig{q, 0, w}
+				if ans, _ := post.Contains(q, w); ans != forward[config] {
+					t.Error("On test: post* for " + test.descriptor + ", error: wrong answer for state " + q + " and stack " + w)
+				}
+				backward := reachableConfigs(p, []pdaConfig{config}, 8)[target]
+				if ans, _ := pre.Contains(q, w); ans != backward {
+					t.Error("On test: pre* for " + test.descriptor + ", error: wrong answer for state " + q + " and stack " + w)
+				}
+			}
+		}
+	}
+}
+
+func TestPAutomatonFromDFAs(t *testing.T) {
+	p, _ := makeAnBnPDA()
+	//stacks with a single Z, at the bottom
+	transition := func(state interface{}, input string) interface{} {
+		switch {
+		case state == "top" && input == "Z":
+			return "bottom"
+		case state == "top":
+			return "top"
+		}
+		return "dead"
+	}
+	d, err := NewDFA(mapset.NewSet("top", "bottom", "dead"), mapset.NewSet("A", "Z"), transition, "top", mapset.NewSet("bottom"))
+	if err != nil {
+		t.Error("On test: P-automaton from DFAs, error: " + err.Error())
+		t.FailNow()
+	}
+	c, err := NewPAutomatonFromDFAs(p, map[string]*DFA{"q1": d})
+	if err != nil {
+		t.Error("On test: P-automaton from DFAs, error: " + err.Error())
+		t.FailNow()
+	}
+	var tests = []struct {
+		state, stack string
+		member       bool
+	}{
+		{"q1", "Z", true},
+		{"q1", "AAZ", true},
+		{"q1", "ZA", false},
+		{"q0", "Z", false},
+	}
+	for _, test := range tests {
+		if ans, err := c.Contains(test.state, test.stack); ans != test.member || err != nil {
+			t.Error("On test: P-automaton from DFAs, error: wrong answer for state " + test.state + " and stack " + test.stack)
+		}
+	}
+
+	//everything from which the accept state q2 is reachable, read back as a DFA
+	pre, err := p.PreStar(c)
+	if err != nil {
+		t.Error("On test: pre* of P-automaton from DFAs, error: " + err.Error())
+		t.FailNow()
+	}
+	back, err := pre.StackDFA("q0")
+	if err != nil {
+		t.Error("On test: stack DFA of pre*, error: " + err.Error())
+		t.FailNow()
+	}
+	for _, w := range allStrings([]string{"A", "Z"}, 5) {
+		want, _ := pre.Contains("q0", w)
+		if ans, _ := back.Simulate(w); ans != want {
+			t.Error("On test: stack DFA of pre*, error: wrong answer for stack " + w)
+		}
+	}
+	if _, err := p.PostStar(c); err != nil {
+		t.Error("On test: post* of P-automaton from DFAs, error: " + err.Error())
+	}
+	other, _ := makeEvenPalindromePDA()
+	if _, err := other.PostStar(c); err == nil {
+		t.Error("On test: post* with a P-automaton for another PDA, error: should have been reported")
+	}
+}
@@ -0,0 +1,133 @@
+package gocompute
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// A Pumping is a decomposition w = xyz of a word, with y non-empty, as in the pumping lemma for regular languages:
+// if a DFA accepts xyz, it also accepts xy^iz for every i >= 0.
+type Pumping struct {
+	X, Y, Z string
+}
+
+// Pump returns the word xy^iz.
+func (p Pumping) Pump(i int) string {
+	return p.X + strings.Repeat(p.Y, i) + p.Z
+}
+
+//breadth-first search from a state for the shortest (then lexicographically least)
+//word leading to a state satisfying goal. if nonEmpty is set, the empty word is
+//not considered, so a state can be its own goal only through a cycle.
+func shortestPath(d *DFA, from interface{}, nonEmpty bool, goal func(state interface{}) bool) (string, bool) {
+	symbols := sortedAlphabet(d.alphabet)
+	var order []interface{}
+	access := map[interface{}]string{}
+	if nonEmpty {
+		for _, a := range symbols {
+			next := d.transition(from, a)
+			if _, seen := access[next]; !seen {
+				access[next] = a
+				order = append(order, next)
+			}
+		}
+	} else {
+		order = append(order, from)
+		access[from] = ""
+	}
+	for i := 0; i < len(order); i++ {
+		state := order[i]
+		if goal(state) {
+			return access[state], true
+		}
+		for _, a := range symbols {
+			next := d.transition(state, a)
+			if _, seen := access[next]; !seen {
+				access[next] = access[state] + a
+				order = append(order, next)
+			}
+		}
+	}
+	return "", false
+}
+
+// Given a DFA d and a word w accepted by d with at least as many symbols as d has states, d.PumpingDecomposition(w)
+// returns a decomposition w = xyz with y non-empty and |xy| no longer than the number of states. y is the part of w
+// read between the first two visits to the first state that repeats in the run of d on w. The decomposition is
+// checked with d.CheckPumping for i from 0 to 3 before it is returned.
+func (d DFA) PumpingDecomposition(w string) (*Pumping, error) {
+	ans, err := d.Simulate(w)
+	if err != nil {
+		return nil, err
+	}
+	if !ans {
+		return nil, errors.New("gocompute/dfa: string " + w + " is not accepted by DFA")
+	}
+	symbols := []rune(w)
+	if len(symbols) < d.states.Cardinality() {
+		return nil, errors.New("gocompute/dfa: string " + w + " is shorter than the number of states")
+	}
+
+	//seen maps each state in the run to the number of symbols read before first reaching it
+	seen := map[interface{}]int{d.start: 0}
+	state := d.start
+	for i, r := range symbols {
+		state = d.transition(state, string(r))
+		if first, ok := seen[state]; ok {
+			p := &Pumping{string(symbols[:first]), string(symbols[first : i+1]), string(symbols[i+1:])}
+			if err := d.CheckPumping(p, 0, 3); err != nil {
+				return nil, err
+			}
+			return p, nil
+		}
+		seen[state] = i + 1
+	}
+	//unreachable: a run of n symbols visits n+1 states, so one of d's n states repeats
+	return nil, errors.New("gocompute/dfa: no repeated state in run")
+}
+
+// Given a DFA d and a decomposition p, d.CheckPumping(p, from, to) checks that d accepts xy^iz for every i from
+// from to to inclusive, returning an error naming the first i for which it does not.
+func (d DFA) CheckPumping(p *Pumping, from, to int) error {
+	if p.Y == "" {
+		return errors.New("gocompute/dfa: pumped substring y must be non-empty")
+	}
+	for i := from; i <= to; i++ {
+		ans, err := d.Simulate(p.Pump(i))
+		if err != nil {
+			return err
+		}
+		if !ans {
+			return errors.New("gocompute/dfa: pumping fails for i = " + strconv.Itoa(i))
+		}
+	}
+	return nil
+}
+
+// Given a DFA d, d.IsInfinite() reports whether L(d) is infinite. If it is, it also returns a certificate: a
+// decomposition xyz where x reaches a state q, y is a cycle from q back to q, and z leads from q to an accept state,
+// so that xy^iz is accepted for every i. The first such q in breadth-first order from the start state is used, with
+// the shortest x, y and z.
+func (d DFA) IsInfinite() (bool, *Pumping, error) {
+	ans, err := d.CheckDFA()
+	if ans == false && err != nil {
+		return false, nil, errors.New("gocompute/dfa: invalid DFA: " + err.Error())
+	}
+	states, access := reachableStates(&d)
+	accepting := func(state interface{}) bool {
+		return d.accept.Contains(state)
+	}
+	for _, q := range states {
+		z, ok := shortestPath(&d, q, false, accepting)
+		if !ok {
+			continue
+		}
+		y, ok := shortestPath(&d, q, true, func(state interface{}) bool { return state == q })
+		if !ok {
+			continue
+		}
+		return true, &Pumping{access[q], y, z}, nil
+	}
+	return false, nil, nil
+}
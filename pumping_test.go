@@ -0,0 +1,65 @@
+package gocompute
+
+import (
+	"testing"
+)
+
+var pumpingTests = []struct {
+	d          *DFA
+	err        error
+	w          string
+	pumping    Pumping
+	descriptor string
+}{
+	{d1, d1err, "0011", Pumping{"", "0", "011"}, "DFA accepting strings with even number of 1s"},
+	{d1, d1err, "11", Pumping{"", "11", ""}, "DFA accepting strings with even number of 1s, pumping a pair of 1s"},
+	{d4, d4err, "10", Pumping{"", "1", "0"}, "DFA accepting strings with odd number of 0s"},
+	{uniond1, uniond1err, "0110", Pumping{"0", "11", "0"}, "Union of DFAs accepting even number of 1s or even number of 0s"},
+}
+
+func TestDFAPumpingDecomposition(t *testing.T) {
+	for _, test := range pumpingTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		p, err := test.d.PumpingDecomposition(test.w)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if *p != test.pumping {
+			t.Error("On test: " + test.descriptor + ", error: expected x, y, z = " + test.pumping.X + ", " + test.pumping.Y + ", " + test.pumping.Z + ", got " + p.X + ", " + p.Y + ", " + p.Z)
+		}
+		if err := test.d.CheckPumping(p, 0, 10); err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+		}
+	}
+	if _, err := d1.PumpingDecomposition("1"); err == nil {
+		t.Error("On test: rejected string, error: decomposition of a rejected string should fail")
+	}
+	if _, err := uniond1.PumpingDecomposition("00"); err == nil {
+		t.Error("On test: short string, error: decomposition of a string shorter than the number of states should fail")
+	}
+}
+
+func TestDFAIsInfinite(t *testing.T) {
+	for _, d := range []*DFA{d1, d4, d7, uniond1, interd1} {
+		infinite, p, err := d.IsInfinite()
+		if err != nil {
+			t.Error("On test: infinite language, error: " + err.Error())
+			continue
+		}
+		if !infinite {
+			t.Error("On test: infinite language, error: language should be infinite")
+			continue
+		}
+		if err := d.CheckPumping(p, 0, 5); err != nil {
+			t.Error("On test: infinite language, error: certificate does not pump: " + err.Error())
+		}
+	}
+	infinite, _, err := d8.IsInfinite()
+	if err != nil || infinite {
+		t.Error("On test: empty language, error: language should be finite")
+	}
+}
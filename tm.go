@@ -0,0 +1,294 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"reflect"
+	"strings"
+)
+
+// Internal representation of a TM
+type TM struct {
+	states        mapset.Set
+	inputAlphabet mapset.Set
+	tapeAlphabet  mapset.Set
+	blank         string
+	transition    func(state, symbol string) (action TMAction)
+	start         string
+	accept        string
+	reject        string
+}
+
+// TMMove is the direction a TM head moves in after writing.
+type TMMove int
+
+const (
+	// MoveLeft moves the head one cell to the left.
+	MoveLeft TMMove = iota
+	// MoveRight moves the head one cell to the right.
+	MoveRight
+	// MoveStay leaves the head where it is.
+	MoveStay
+)
+
+// String returns L, R or S.
+func (m TMMove) String() string {
+	switch m {
+	case MoveLeft:
+		return "L"
+	case MoveRight:
+		return "R"
+	case MoveStay:
+		return "S"
+	}
+	return "?"
+}
+
+// A TMAction is the value of a TM's transition function: the state to enter, the symbol to write over the one read,
+// and the direction to move the head in.
+type TMAction struct {
+	State string
+	Write string
+	Move  TMMove
+}
+
+// TMOutcome says how a run of a TM ended.
+type TMOutcome int
+
+const (
+	// TMAccept means the TM entered its accept state.
+	TMAccept TMOutcome = iota
+	// TMReject means the TM entered its reject state.
+	TMReject
+	// TMTimeout means the TM was still running when the step bound was reached.
+	TMTimeout
+)
+
+// String returns accept, reject or timeout.
+func (o TMOutcome) String() string {
+	switch o {
+	case TMAccept:
+		return "accept"
+	case TMReject:
+		return "reject"
+	case TMTimeout:
+		return "timeout"
+	}
+	return "unknown"
+}
+
+// A TMResult describes the end of a run of a TM: its outcome, the state it stopped in, the number of steps taken, and
+// the tape. Tape runs from the leftmost to the rightmost non-blank cell, widened if need be to take in the head, and
+// Head is the position of the head within it.
+type TMResult struct {
+	Outcome TMOutcome
+	State   string
+	Steps   int
+	Tape    string
+	Head    int
+}
+
+// Constructor method for creating a TM, a deterministic single-tape Turing machine. Takes as input a set of string
+// states, a set of strings representing the input alphabet, a set of strings representing the tape alphabet, the
+// blank symbol, a transition function, a start state, an accept state and a reject state. Returns a pointer to the
+// newly created TM and an error, which is non-nil if the input was improperly formatted.
+//
+// Input and tape symbols are single-character strings. The input alphabet must be a subset of the tape alphabet, and
+// the blank must be in the tape alphabet but not the input alphabet. The accept and reject states must be distinct.
+// The transition function must return a valid TMAction, entering a state and writing a tape symbol, for every state
+// other than the accept and reject states and every tape symbol. The tape is unbounded in both directions.
+func NewTM(states,
+	inputAlphabet,
+	tapeAlphabet mapset.Set,
+	blank string,
+	transition func(state, symbol string) (action TMAction),
+	start,
+	accept,
+	reject string) (*TM, error) {
+
+	m := &TM{states, inputAlphabet, tapeAlphabet, blank, transition, start, accept, reject}
+	ans, err := m.CheckTM()
+	if ans != true && err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Checks to make sure a given TM m is properly formatted with correct input data.
+func (m TM) CheckTM() (bool, error) {
+	ans, err := m.checkStructure()
+	if ans != true && err != nil {
+		return false, err
+	}
+
+	//check that for every state that does not halt and every tape symbol, the transition
+	//function enters a state, writes a tape symbol and moves the head
+	for _, state := range sortedAlphabet(m.states) {
+		if state == m.accept || state == m.reject {
+			continue
+		}
+		for _, a := range sortedAlphabet(m.tapeAlphabet) {
+			action := m.transition(state, a)
+			if !m.states.Contains(action.State) || !m.tapeAlphabet.Contains(action.Write) {
+				return false, errors.New("gocompute/tm: invalid transition function")
+			}
+			if action.Move < MoveLeft || action.Move > MoveStay {
+				return false, errors.New("gocompute/tm: transition function returns an unknown move")
+			}
+		}
+	}
+	return true, nil
+}
+
+//checks the states and alphabets of m, leaving out the transition function
+func (m TM) checkStructure() (bool, error) {
+	//check that all states are strings
+	for _, elem := range m.states.ToSlice() {
+		if reflect.TypeOf(elem).Kind() != reflect.String {
+			return false, errors.New("gocompute/tm: set of states contains non-string type")
+		}
+	}
+
+	//check that all symbols are single-character strings, and the alphabets fit together
+	if !checkSymbols(m.inputAlphabet) {
+		return false, errors.New("gocompute/tm: input alphabet contains a symbol which is not a single-character string")
+	}
+	if !checkSymbols(m.tapeAlphabet) {
+		return false, errors.New("gocompute/tm: tape alphabet contains a symbol which is not a single-character string")
+	}
+	if !m.tapeAlphabet.IsSuperset(m.inputAlphabet) {
+		return false, errors.New("gocompute/tm: input alphabet not a subset of tape alphabet")
+	}
+	if !m.tapeAlphabet.Contains(m.blank) {
+		return false, errors.New("gocompute/tm: blank symbol not in tape alphabet")
+	}
+	if m.inputAlphabet.Contains(m.blank) {
+		return false, errors.New("gocompute/tm: blank symbol in input alphabet")
+	}
+
+	//check that the start, accept and reject states are valid
+	if !m.states.Contains(m.start) {
+		return false, errors.New("gocompute/tm: start state not in set of states")
+	}
+	if !m.states.Contains(m.accept) || !m.states.Contains(m.reject) {
+		return false, errors.New("gocompute/tm: accept or reject state not in set of states")
+	}
+	if m.accept == m.reject {
+		return false, errors.New("gocompute/tm: accept and reject states must be distinct")
+	}
+	return true, nil
+}
+
+//a tape unbounded in both directions. cell i is stored at cells[i+offset], and
+//cells outside the slice are blank
+type tmTape struct {
+	cells  []string
+	offset int
+	blank  string
+}
+
+//returns a tape holding w from cell 0 onwards
+func newTMTape(w, blank string) *tmTape {
+	t := &tmTape{nil, 0, blank}
+	for _, r := range w {
+		t.cells = append(t.cells, string(r))
+	}
+	return t
+}
+
+//returns the symbol in cell i
+func (t *tmTape) read(i int) string {
+	if i+t.offset < 0 || i+t.offset >= len(t.cells) {
+		return t.blank
+	}
+	return t.cells[i+t.offset]
+}
+
+//writes a symbol to cell i, growing the tape as needed
+func (t *tmTape) write(i int, symbol string) {
+	if i+t.offset < 0 {
+		//double the room on the left, so that moving left takes amortized constant time
+		grow := len(t.cells) + 1
+		if need := -(i + t.offset); need > grow {
+			grow = need
+		}
+		cells := make([]string, grow, grow+len(t.cells))
+		for j := range cells {
+			cells[j] = t.blank
+		}
+		t.cells = append(cells, t.cells...)
+		t.offset += grow
+	}
+	for i+t.offset >= len(t.cells) {
+		t.cells = append(t.cells, t.blank)
+	}
+	t.cells[i+t.offset] = symbol
+}
+
+//returns the tape from its leftmost to its rightmost non-blank cell, widened to
+//take in cell head, together with the position of head within it
+func (t *tmTape) contents(head int) (string, int) {
+	lo, hi := head, head
+	for i, s := range t.cells {
+		if s != t.blank {
+			if i-t.offset < lo {
+				lo = i - t.offset
+			}
+			if i-t.offset > hi {
+				hi = i - t.offset
+			}
+		}
+	}
+	symbols := make([]string, 0, hi-lo+1)
+	for i := lo; i <= hi; i++ {
+		symbols = append(symbols, t.read(i))
+	}
+	return strings.Join(symbols, ""), head - lo
+}
+
+// Given a TM m, a string w and a step bound, m.Run(w, maxSteps) runs m on w, starting in the start state with w on
+// the tape and the head on its first symbol, for at most maxSteps steps. It returns a TMResult saying whether m
+// accepted, rejected or was still running when the bound was reached, along with the final state and tape.
+func (m TM) Run(w string, maxSteps int) (*TMResult, error) {
+	ans, err := m.CheckTM()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/tm: invalid TM: " + err.Error())
+	}
+	for _, r := range w {
+		if !m.inputAlphabet.Contains(string(r)) {
+			return nil, errors.New("gocompute/tm: string to run on not in input alphabet of TM")
+		}
+	}
+	if maxSteps < 0 {
+		return nil, errors.New("gocompute/tm: step bound must not be negative")
+	}
+
+	tape, head, state := newTMTape(w, m.blank), 0, m.start
+	steps := 0
+	for ; steps < maxSteps && state != m.accept && state != m.reject; steps++ {
+		state, head = m.step(tape, state, head)
+	}
+	result := &TMResult{Outcome: TMTimeout, State: state, Steps: steps}
+	switch state {
+	case m.accept:
+		result.Outcome = TMAccept
+	case m.reject:
+		result.Outcome = TMReject
+	}
+	result.Tape, result.Head = tape.contents(head)
+	return result, nil
+}
+
+//makes one move of m on the tape from state with the head at cell head, and
+//returns the new state and head position
+func (m TM) step(tape *tmTape, state string, head int) (string, int) {
+	action := m.transition(state, tape.read(head))
+	tape.write(head, action.Write)
+	switch action.Move {
+	case MoveLeft:
+		head--
+	case MoveRight:
+		head++
+	}
+	return action.State, head
+}
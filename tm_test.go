@@ -0,0 +1,152 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"testing"
+)
+
+func makeAnBnTM() (*TM, error) {
+	//q0 crosses off an a as X, q1 finds the first b and crosses it off as Y, q2 returns
+	//to the last X, and q3 checks that only Ys are left once the a's run out
+	transition := func(state, symbol string) TMAction {
+		switch {
+		case state == "q0" && symbol == "a":
+			return TMAction{"q1", "X", MoveRight}
+		case state == "q0" && symbol == "Y":
+			return TMAction{"q3", "Y", MoveRight}
+		case state == "q0" && symbol == "_":
+			return TMAction{"accept", "_", MoveStay}
+		case state == "q1" && (symbol == "a" || symbol == "Y"):
+			return TMAction{"q1", symbol, MoveRight}
+		case state == "q1" && symbol == "b":
+			return TMAction{"q2", "Y", MoveLeft}
+		case state == "q2" && (symbol == "a" || symbol == "Y"):
+			return TMAction{"q2", symbol, MoveLeft}
+		case state == "q2" && symbol == "X":
+			return TMAction{"q0", "X", MoveRight}
+		case state == "q3" && symbol == "Y":
+			return TMAction{"q3", "Y", MoveRight}
+		case state == "q3" && symbol == "_":
+			return TMAction{"accept", "_", MoveStay}
+		}
+		return TMAction{"reject", symbol, MoveStay}
+	}
+	return NewTM(mapset.NewSet("q0", "q1", "q2", "q3", "accept", "reject"), mapset.NewSet("a", "b"), mapset.NewSet("a", "b", "X", "Y", "_"), "_", transition, "q0", "accept", "reject")
+}
+
+func makeIncrementTM() (*TM, error) {
+	//q0 finds the end of a binary number, and q1 adds one to it moving left, carrying
+	//past the left end of the input if need be
+	transition := func(state, symbol string) TMAction {
+		switch {
+		case state == "q0" && symbol == "_":
+			return TMAction{"q1", "_", MoveLeft}
+		case state == "q0":
+			return TMAction{"q0", symbol, MoveRight}
+		case state == "q1" && symbol == "1":
+			return TMAction{"q1", "0", MoveLeft}
+		}
+		return TMAction{"accept", "1", MoveStay}
+	}
+	return NewTM(mapset.NewSet("q0", "q1", "accept", "reject"), mapset.NewSet("0", "1"), mapset.NewSet("0", "1", "_"), "_", transition, "q0", "accept", "reject")
+}
+
+func makeRunawayTM() (*TM, error) {
+	transition := func(state, symbol string) TMAction {
+		return TMAction{"q0", symbol, MoveRight}
+	}
+	return NewTM(mapset.NewSet("q0", "accept", "reject"), mapset.NewSet("a"), mapset.NewSet("a", "_"), "_", transition, "q0", "accept", "reject")
+}
+
+var tm1, tm1err = makeAnBnTM()
+var tm2, tm2err = makeIncrementTM()
+var tm3, tm3err = makeRunawayTM()
+
+var tmRunTests = []struct {
+	m          *TM
+	err        error
+	input      string
+	maxSteps   int
+	outcome    TMOutcome
+	tape       string
+	head       int
+	descriptor string
+}{
+	{tm1, tm1err, "aabb", 100, TMAccept, "XXYY_", 4, "TM deciding a^n b^n on aabb"},
+	{tm1, tm1err, "", 100, TMAccept, "_", 0, "TM deciding a^n b^n on the empty string"},
+	{tm1, tm1err, "aab", 100, TMReject, "XXY_", 3, "TM deciding a^n b^n on aab"},
+	{tm1, tm1err, "aabb", 5, TMTimeout, "XaYb", 1, "TM deciding a^n b^n cut short"},
+	{tm2, tm2err, "1011", 100, TMAccept, "1100", 1, "TM incrementing 1011"},
+	{tm2, tm2err, "111", 100, TMAccept, "1000", 0, "TM incrementing 111, growing the tape to the left"},
+	{tm2, tm2err, "", 100, TMAccept, "1", 0, "TM incrementing the empty string"},
+	{tm3, tm3err, "aa", 10, TMTimeout, "aa_________", 10, "TM running off to the right"},
+}
+
+func TestTMRun(t *testing.T) {
+	for _, test := range tmRunTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		result, err := test.m.Run(test.input, test.maxSteps)
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if result.Outcome != test.outcome {
+			t.Error("On test: " + test.descriptor + ", error: expected outcome " + test.outcome.String() + ", got " + result.Outcome.String())
+		}
+		if result.Tape != test.tape || result.Head != test.head {
+			t.Error("On test: " + test.descriptor + ", error: expected tape " + test.tape + " with head at " + strconv.Itoa(test.head) + ", got " + result.Tape + " with head at " + strconv.Itoa(result.Head))
+		}
+		if test.outcome == TMTimeout && result.Steps != test.maxSteps {
+			t.Error("On test: " + test.descriptor + ", error: expected to run for " + strconv.Itoa(test.maxSteps) + " steps, ran for " + strconv.Itoa(result.Steps))
+		}
+	}
+	if _, err := tm1.Run("abc", 10); err == nil {
+		t.Error("On test: TM run on a string outside its input alphabet, error: should have been reported")
+	}
+}
+
+func TestCheckTM(t *testing.T) {
+	valid := func(state, symbol string) TMAction {
+		return TMAction{"accept", symbol, MoveRight}
+	}
+	unknownState := func(state, symbol string) TMAction {
+		return TMAction{"q9", symbol, MoveRight}
+	}
+	unknownSymbol := func(state, symbol string) TMAction {
+		return TMAction{"accept", "z", MoveRight}
+	}
+	unknownMove := func(state, symbol string) TMAction {
+		return TMAction{"accept", symbol, TMMove(7)}
+	}
+	states := mapset.NewSet("q0", "accept", "reject")
+	var tests = []struct {
+		inputAlphabet mapset.Set
+		blank         string
+		transition    func(state, symbol string) TMAction
+		accept        string
+		valid         bool
+		descriptor    string
+	}{
+		{mapset.NewSet("a"), "_", valid, "accept", true, "valid TM"},
+		{mapset.NewSet("a"), "_", unknownState, "accept", false, "transition to an unknown state"},
+		{mapset.NewSet("a"), "_", unknownSymbol, "accept", false, "transition writing an unknown symbol"},
+		{mapset.NewSet("a"), "_", unknownMove, "accept", false, "transition with an unknown move"},
+		{mapset.NewSet("a", "_"), "_", valid, "accept", false, "blank in the input alphabet"},
+		{mapset.NewSet("a", "b"), "_", valid, "accept", false, "input symbol missing from the tape alphabet"},
+		{mapset.NewSet("a"), "#", valid, "accept", false, "blank missing from the tape alphabet"},
+		{mapset.NewSet("a"), "_", valid, "reject", false, "accept and reject states equal"},
+	}
+	for _, test := range tests {
+		_, err := NewTM(states, test.inputAlphabet, mapset.NewSet("a", "_"), test.blank, test.transition, "q0", test.accept, "reject")
+		if test.valid && err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+		}
+		if !test.valid && err == nil {
+			t.Error("On test: " + test.descriptor + ", error: invalid TM should have been reported")
+		}
+	}
+}
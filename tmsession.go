@@ -0,0 +1,134 @@
+package gocompute
+
+import (
+	"errors"
+)
+
+// A TMSession runs a TM on an input one step at a time, for debugging. It keeps a history of the steps taken so that
+// they can be undone, and can run until it reaches a breakpoint on a state or a tape symbol.
+type TMSession struct {
+	m            *TM
+	tape         *tmTape
+	head         int
+	state        string
+	history      []tmUndo
+	stateBreaks  map[string]bool
+	symbolBreaks map[string]bool
+}
+
+//what a step overwrote: the state and head position before it, and the symbol it wrote over
+type tmUndo struct {
+	state  string
+	head   int
+	symbol string
+}
+
+// A TMSnapshot is the configuration of a TM at some point in a session: its state, the number of steps taken to get
+// there, and the tape, from its leftmost to its rightmost non-blank cell widened to take in the head, with the
+// position of the head within it.
+type TMSnapshot struct {
+	State string
+	Steps int
+	Tape  string
+	Head  int
+}
+
+// Given a TM m and a string w, m.NewSession(w) returns a pointer to a new TMSession with m in its start state, w on
+// the tape and the head on its first symbol, and no breakpoints.
+func (m TM) NewSession(w string) (*TMSession, error) {
+	ans, err := m.CheckTM()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/tm: invalid TM: " + err.Error())
+	}
+	for _, r := range w {
+		if !m.inputAlphabet.Contains(string(r)) {
+			return nil, errors.New("gocompute/tm: string to run on not in input alphabet of TM")
+		}
+	}
+	return &TMSession{&m, newTMTape(w, m.blank), 0, m.start, nil, map[string]bool{}, map[string]bool{}}, nil
+}
+
+// Halted returns true if the TM is in its accept or reject state.
+func (s *TMSession) Halted() bool {
+	return s.state == s.m.accept || s.state == s.m.reject
+}
+
+// Snapshot returns the current configuration of the TM.
+func (s *TMSession) Snapshot() TMSnapshot {
+	tape, head := s.tape.contents(s.head)
+	return TMSnapshot{s.state, len(s.history), tape, head}
+}
+
+// Configuration renders the current configuration of the TM as uq v, where the tape holds uv with the head on the
+// first symbol of v and q is the state, so that the TM in state q3 reading the 1 of 0110 gives 01q3 10. The space
+// keeps state names apart from tape symbols. The tape is shown as in Snapshot.
+func (s *TMSession) Configuration() string {
+	tape, head := s.tape.contents(s.head)
+	symbols := []rune(tape)
+	return string(symbols[:head]) + s.state + " " + string(symbols[head:])
+}
+
+// Step makes one move of the TM and returns true, or returns false if it has halted.
+func (s *TMSession) Step() bool {
+	if s.Halted() {
+		return false
+	}
+	s.history = append(s.history, tmUndo{s.state, s.head, s.tape.read(s.head)})
+	s.state, s.head = s.m.step(s.tape, s.state, s.head)
+	return true
+}
+
+// StepBack undoes the last move of the TM and returns true, or returns false if there is none.
+func (s *TMSession) StepBack() bool {
+	if len(s.history) == 0 {
+		return false
+	}
+	last := s.history[len(s.history)-1]
+	s.history = s.history[:len(s.history)-1]
+	s.tape.write(last.head, last.symbol)
+	s.state, s.head = last.state, last.head
+	return true
+}
+
+// BreakOnState sets a breakpoint which stops Continue after every step which leaves the TM in the given state,
+// including steps from the state back to itself.
+func (s *TMSession) BreakOnState(state string) error {
+	if !s.m.states.Contains(state) {
+		return errors.New("gocompute/tm: breakpoint state not in set of states")
+	}
+	s.stateBreaks[state] = true
+	return nil
+}
+
+// BreakOnSymbol sets a breakpoint which stops Continue whenever the head moves onto, or a move leaves it on, the
+// given tape symbol.
+func (s *TMSession) BreakOnSymbol(symbol string) error {
+	if !s.m.tapeAlphabet.Contains(symbol) {
+		return errors.New("gocompute/tm: breakpoint symbol not in tape alphabet")
+	}
+	s.symbolBreaks[symbol] = true
+	return nil
+}
+
+// ClearBreakpoints removes all breakpoints.
+func (s *TMSession) ClearBreakpoints() {
+	s.stateBreaks = map[string]bool{}
+	s.symbolBreaks = map[string]bool{}
+}
+
+// Given a session s and a step bound, s.Continue(maxSteps) steps the TM until it halts, it reaches a breakpoint after
+// a step, or maxSteps steps have been taken, and returns true if it stopped at a breakpoint. The first step is taken
+// even if the TM starts at a breakpoint, and does not stop at a state breakpoint on the state the TM starts in, so
+// that calling Continue again moves on to the next one.
+func (s *TMSession) Continue(maxSteps int) bool {
+	for i := 0; i < maxSteps; i++ {
+		previous := s.state
+		if !s.Step() {
+			return false
+		}
+		if (s.stateBreaks[s.state] && (i > 0 || s.state != previous)) || s.symbolBreaks[s.tape.read(s.head)] {
+			return true
+		}
+	}
+	return false
+}
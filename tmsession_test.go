@@ -0,0 +1,92 @@
+package gocompute
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestTMSessionStep(t *testing.T) {
+	s, err := tm1.NewSession("aabb")
+	if err != nil {
+		t.Error("On test: stepping a TM session, error: " + err.Error())
+		t.FailNow()
+	}
+	var configurations = []string{"q0 aabb", "Xq1 abb", "Xaq1 bb", "Xq2 aYb", "q2 XaYb", "Xq0 aYb"}
+	var snapshots []TMSnapshot
+	for i, c := range configurations {
+		if s.Configuration() != c {
+			t.Error("On test: stepping a TM session, error: expected configuration " + c + " after " + strconv.Itoa(i) + " steps, got " + s.Configuration())
+		}
+		snapshots = append(snapshots, s.Snapshot())
+		s.Step()
+	}
+	for !s.Halted() {
+		snapshots = append(snapshots, s.Snapshot())
+		s.Step()
+	}
+	if s.Step() {
+		t.Error("On test: stepping a TM session, error: step should not be taken once halted")
+	}
+	if snap := s.Snapshot(); snap.State != "accept" || snap.Tape != "XXYY_" || snap.Steps != len(snapshots) {
+		t.Error("On test: stepping a TM session, error: wrong final snapshot " + snap.State + " " + snap.Tape)
+	}
+
+	//stepping back should retrace the same configurations
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !s.StepBack() {
+			t.Error("On test: stepping back a TM session, error: step back refused with history left")
+			t.FailNow()
+		}
+		if s.Snapshot() != snapshots[i] {
+			t.Error("On test: stepping back a TM session, error: wrong snapshot after stepping back to step " + strconv.Itoa(i))
+		}
+	}
+	if s.StepBack() {
+		t.Error("On test: stepping back a TM session, error: step back should be refused at the start")
+	}
+}
+
+func TestTMSessionBreakpoints(t *testing.T) {
+	s, err := tm1.NewSession("aabb")
+	if err != nil {
+		t.Error("On test: TM session breakpoints, error: " + err.Error())
+		t.FailNow()
+	}
+	if s.BreakOnState("q9") == nil || s.BreakOnSymbol("z") == nil {
+		t.Error("On test: TM session breakpoints, error: unknown breakpoint should have been reported")
+	}
+	s.BreakOnState("q2")
+	var stops = []string{"Xq2 aYb", "XXq2 YY"}
+	for _, c := range stops {
+		if !s.Continue(100) || s.Configuration() != c {
+			t.Error("On test: TM session breakpoints, error: expected to stop at " + c + ", got " + s.Configuration())
+		}
+	}
+	s.ClearBreakpoints()
+	s.BreakOnSymbol("_")
+	if !s.Continue(100) || s.Configuration() != "XXYYq3 _" {
+		t.Error("On test: TM session breakpoints, error: expected to stop at XXYYq3 _, got " + s.Configuration())
+	}
+	s.ClearBreakpoints()
+	if s.Continue(100) || !s.Halted() {
+		t.Error("On test: TM session breakpoints, error: expected to run until halting")
+	}
+	if s.Continue(100) {
+		t.Error("On test: TM session breakpoints, error: halted TM should not reach a breakpoint")
+	}
+
+	//q2 loops on itself while it walks back over the a's, so after entering q2 each
+	//Continue stops again inside the loop, having taken the first step from q2 freely
+	l, _ := tm1.NewSession("aaabbb")
+	l.BreakOnState("q2")
+	for _, c := range []string{"Xaq2 aYbb", "q2 XaaYbb", "XXaq2 YYb", "Xq2 XaYYb"} {
+		if !l.Continue(100) || l.Configuration() != c {
+			t.Error("On test: TM session breakpoint on a looping state, error: expected to stop at " + c + ", got " + l.Configuration())
+		}
+	}
+
+	r, _ := tm3.NewSession("a")
+	if r.Continue(7) || r.Snapshot().Steps != 7 {
+		t.Error("On test: TM session breakpoints, error: expected to stop after the step bound")
+	}
+}
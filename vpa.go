@@ -0,0 +1,480 @@
+package gocompute
+
+import (
+	"errors"
+	"github.com/jophish/golang-set"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Internal representation of a VPA
+type VPA struct {
+	states        mapset.Set
+	calls         mapset.Set
+	returns       mapset.Set
+	internals     mapset.Set
+	stackAlphabet mapset.Set
+	transition    func(state, input, stackSymbol string) (moves mapset.Set)
+	start         string
+	accept        mapset.Set
+}
+
+// Constructor method for creating a VPA, a visibly pushdown automaton, which is a PDA whose input symbol alone decides
+// what it does to the stack. Takes as input a set of string states, three disjoint sets of single-character strings
+// partitioning the input alphabet into call, return and internal symbols, a set of strings representing the stack
+// alphabet, a transition function, a start state and a set of accept states. Returns a pointer to the newly created
+// VPA and an error, which is non-nil if the input was improperly formatted. A VPA accepts by final state.
+//
+// The transition function returns a set of PDAMoves, or nil if there are none. On a call symbol it is called with
+// stack symbol "", and every move pushes exactly one stack symbol, given as its Push. On a return symbol it is called
+// with the top stack symbol, which every move pops, or with "" when the stack is empty, in which case the stack stays
+// empty. On an internal symbol it is called with stack symbol "", and the stack is left alone. Moves on returns and
+// internal symbols push nothing. Since each push is a single symbol, stack symbols may be strings of any length.
+func NewVPA(states,
+	calls,
+	returns,
+	internals,
+	stackAlphabet mapset.Set,
+	transition func(state, input, stackSymbol string) (moves mapset.Set),
+	start string,
+	accept mapset.Set) (*VPA, error) {
+
+	v := &VPA{states, calls, returns, internals, stackAlphabet, transition, start, accept}
+	ans, err := v.CheckVPA()
+	if ans != true && err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Alphabet returns the input alphabet of v, the union of its call, return and internal symbols.
+func (v VPA) Alphabet() mapset.Set {
+	return v.calls.Union(v.returns).Union(v.internals)
+}
+
+//returns the moves of v from state on input with the given stack symbol as a slice
+func (v VPA) moves(state, input, stackSymbol string) []PDAMove {
+	moves := v.transition(state, input, stackSymbol)
+	if moves == nil {
+		return nil
+	}
+	result := make([]PDAMove, 0, moves.Cardinality())
+	for elem := range moves.Iter() {
+		result = append(result, elem.(PDAMove))
+	}
+	return result
+}
+
+// Checks to make sure a given VPA v is properly formatted with correct input data.
+func (v VPA) CheckVPA() (bool, error) {
+	//check that all states and stack symbols are strings
+	for _, elem := range v.states.ToSlice() {
+		if reflect.TypeOf(elem).Kind() != reflect.String {
+			return false, errors.New("gocompute/vpa: set of states contains non-string type")
+		}
+	}
+	for _, elem := range v.stackAlphabet.ToSlice() {
+		if reflect.TypeOf(elem).Kind() != reflect.String || elem.(string) == "" {
+			return false, errors.New("gocompute/vpa: stack alphabet contains a symbol which is not a non-empty string")
+		}
+	}
+
+	//check that the input symbols are single-character strings, partitioned into
+	//calls, returns and internal symbols
+	if !checkSymbols(v.calls) || !checkSymbols(v.returns) || !checkSymbols(v.internals) {
+		return false, errors.New("gocompute/vpa: alphabet contains a symbol which is not a single-character string")
+	}
+	if v.calls.Intersect(v.returns).Cardinality() != 0 || v.calls.Intersect(v.internals).Cardinality() != 0 ||
+		v.returns.Intersect(v.internals).Cardinality() != 0 {
+		return false, errors.New("gocompute/vpa: call, return and internal symbols must be disjoint")
+	}
+
+	//check that start state is valid, and the set of accept states is subset of states
+	if !v.states.Contains(v.start) {
+		return false, errors.New("gocompute/vpa: start state not in set of states")
+	}
+	if !v.states.IsSuperset(v.accept) {
+		return false, errors.New("gocompute/vpa: set of accept states not a subset of set of all states")
+	}
+
+	//check that every move enters a state and pushes exactly what its input symbol demands
+	tops := append([]string{""}, sortedAlphabet(v.stackAlphabet)...)
+	for _, state := range sortedAlphabet(v.states) {
+		for _, a := range sortedAlphabet(v.Alphabet()) {
+			for _, top := range tops {
+				if top != "" && !v.returns.Contains(a) {
+					continue
+				}
+				moves := v.transition(state, a, top)
+				if moves == nil {
+					continue
+				}
+				for _, elem := range moves.ToSlice() {
+					move, ok := elem.(PDAMove)
+					if !ok || !v.states.Contains(move.State) {
+						return false, errors.New("gocompute/vpa: invalid transition function")
+					}
+					if v.calls.Contains(a) && !v.stackAlphabet.Contains(move.Push) {
+						return false, errors.New("gocompute/vpa: move on call symbol " + a + " must push one symbol of the stack alphabet")
+					}
+					if !v.calls.Contains(a) && move.Push != "" {
+						return false, errors.New("gocompute/vpa: move on symbol " + a + " must not push")
+					}
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+// Given a VPA v, v.IsDeterministic() returns true if v has at most one move from every state on every input symbol and
+// stack symbol.
+func (v VPA) IsDeterministic() bool {
+	tops := append([]string{""}, sortedAlphabet(v.stackAlphabet)...)
+	for _, state := range sortedAlphabet(v.states) {
+		for _, a := range sortedAlphabet(v.Alphabet()) {
+			for _, top := range tops {
+				if len(v.moves(state, a, top)) > 1 {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+//a summary of the runs of a VPA on the input read so far. pairs holds each (p, q)
+//such that the VPA can go from p, just after the last pending call or at the start
+//if there is none, to q now, and reach holds the states the VPA can be in now
+type vpaSummary struct {
+	pairs map[[2]string]bool
+	reach map[string]bool
+}
+
+//returns a string identifying the summary
+func (s vpaSummary) key() string {
+	var pairs, reach []string
+	for pair := range s.pairs {
+		pairs = append(pairs, strconv.Quote(pair[0])+">"+strconv.Quote(pair[1]))
+	}
+	for q := range s.reach {
+		reach = append(reach, strconv.Quote(q))
+	}
+	sort.Strings(pairs)
+	sort.Strings(reach)
+	return strings.Join(pairs, ",") + "|" + strings.Join(reach, ",")
+}
+
+//a pending call: the summary before it and the call symbol
+type vpaFrame struct {
+	summary vpaSummary
+	call    string
+}
+
+//returns the summary of the runs of v on nothing
+func (v VPA) initialSummary() vpaSummary {
+	return vpaSummary{map[[2]string]bool{{v.start, v.start}: true}, map[string]bool{v.start: true}}
+}
+
+//extends s by an internal symbol, or a return symbol on an empty stack
+func (v VPA) summaryStep(s vpaSummary, a string) vpaSummary {
+	next := vpaSummary{map[[2]string]bool{}, map[string]bool{}}
+	for pair := range s.pairs {
+		for _, m := range v.moves(pair[1], a, "") {
+			next.pairs[[2]string{pair[0], m.State}] = true
+		}
+	}
+	for q := range s.reach {
+		for _, m := range v.moves(q, a, "") {
+			next.reach[m.State] = true
+		}
+	}
+	return next
+}
+
+//returns the summary just after the call symbol a, read with summary s before it
+func (v VPA) summaryCall(s vpaSummary, a string) vpaSummary {
+	next := vpaSummary{map[[2]string]bool{}, map[string]bool{}}
+	for pair := range s.pairs {
+		for _, m := range v.moves(pair[1], a, "") {
+			next.pairs[[2]string{m.State, m.State}] = true
+		}
+	}
+	for q := range s.reach {
+		for _, m := range v.moves(q, a, "") {
+			next.reach[m.State] = true
+		}
+	}
+	return next
+}
+
+//returns the summary after the return symbol a matching the pending call f, read
+//with summary s since the call
+func (v VPA) summaryReturn(f vpaFrame, s vpaSummary, a string) vpaSummary {
+	next := vpaSummary{map[[2]string]bool{}, map[string]bool{}}
+	//the states reached by the return from each state p' just after the call, with the symbol it pushed
+	returns := func(q string, add func(string)) {
+		for _, call := range v.moves(q, f.call, "") {
+			for pair := range s.pairs {
+				if pair[0] != call.State {
+					continue
+				}
+				for _, m := range v.moves(pair[1], a, call.Push) {
+					add(m.State)
+				}
+			}
+		}
+	}
+	for pair := range f.summary.pairs {
+		p := pair[0]
+		returns(pair[1], func(q string) { next.pairs[[2]string{p, q}] = true })
+	}
+	for q := range f.summary.reach {
+		returns(q, func(r string) { next.reach[r] = true })
+	}
+	return next
+}
+
+//reports whether the summary contains an accept state of v
+func (v VPA) summaryAccepts(s vpaSummary) bool {
+	for q := range s.reach {
+		if v.accept.Contains(q) {
+			return true
+		}
+	}
+	return false
+}
+
+// Given a VPA v and a string w, v.Simulate(w) returns true if some run of v on w ends in an accept state. Since the
+// input decides every stack operation, the runs are followed together as summaries, as in Determinize, which takes
+// time linear in the length of w for a fixed VPA.
+func (v VPA) Simulate(w string) (bool, error) {
+	ans, err := v.CheckVPA()
+	if ans != true && err != nil {
+		return false, errors.New("gocompute/vpa: invalid VPA: " + err.Error())
+	}
+	alphabet := v.Alphabet()
+	s := v.initialSummary()
+	var stack []vpaFrame
+	for _, r := range w {
+		a := string(r)
+		switch {
+		case !alphabet.Contains(a):
+			return false, errors.New("gocompute/vpa: string to test not in alphabet of VPA")
+		case v.calls.Contains(a):
+			stack = append(stack, vpaFrame{s, a})
+			s = v.summaryCall(s, a)
+		case v.returns.Contains(a) && len(stack) > 0:
+			s = v.summaryReturn(stack[len(stack)-1], s, a)
+			stack = stack[:len(stack)-1]
+		default:
+			s = v.summaryStep(s, a)
+		}
+	}
+	return v.summaryAccepts(s), nil
+}
+
+// Given a VPA v, v.Determinize() returns a pointer to a new deterministic VPA recognizing the same language, with a
+// move on every input symbol from every state and stack symbol. Unlike PDAs, every VPA has one, found by the summary
+// construction: a state of the new VPA records, for each pair of states p and q of v, whether v can go from p just
+// after the last pending call to q now, along with the states v can be in now, and a call pushes the state before it
+// together with the call symbol, so that the matching return can join the runs before and after the call. States
+// are named s0, s1, ... and stack symbols g0, g1, ... in the order found, with s0 the start state, and only those
+// reachable are built. There may be exponentially many in the number of pairs of states of v.
+func (v VPA) Determinize() (*VPA, error) {
+	ans, err := v.CheckVPA()
+	if ans != true && err != nil {
+		return nil, errors.New("gocompute/vpa: invalid VPA: " + err.Error())
+	}
+	names := map[string]string{}
+	var summaries []vpaSummary
+	name := func(s vpaSummary) string {
+		k := s.key()
+		if n, ok := names[k]; ok {
+			return n
+		}
+		n := "s" + strconv.Itoa(len(summaries))
+		names[k] = n
+		summaries = append(summaries, s)
+		return n
+	}
+	frameNames := map[string]string{}
+	var frames []vpaFrame
+	frameName := func(f vpaFrame) string {
+		k := f.summary.key() + "|" + strconv.Quote(f.call)
+		if n, ok := frameNames[k]; ok {
+			return n
+		}
+		n := "g" + strconv.Itoa(len(frames))
+		frameNames[k] = n
+		frames = append(frames, f)
+		return n
+	}
+	type key struct {
+		state, input, top string
+	}
+	table := map[key]PDAMove{}
+
+	start := name(v.initialSummary())
+	calls, returns, internals := sortedAlphabet(v.calls), sortedAlphabet(v.returns), sortedAlphabet(v.internals)
+	//every state needs a return move on every stack symbol, which may be found later
+	//than the state, so keep going until nothing new turns up
+	for done := 0; ; {
+		n, m := len(summaries), len(frames)
+		for i := 0; i < len(summaries); i++ {
+			s, state := summaries[i], "s"+strconv.Itoa(i)
+			if i >= done {
+				for _, a := range internals {
+					table[key{state, a, ""}] = PDAMove{name(v.summaryStep(s, a)), ""}
+				}
+				for _, a := range calls {
+					push := frameName(vpaFrame{s, a})
+					table[key{state, a, ""}] = PDAMove{name(v.summaryCall(s, a)), push}
+				}
+				for _, a := range returns {
+					table[key{state, a, ""}] = PDAMove{name(v.summaryStep(s, a)), ""}
+				}
+			}
+			for j, f := range frames {
+				for _, a := range returns {
+					k := key{state, a, "g" + strconv.Itoa(j)}
+					if _, ok := table[k]; !ok {
+						table[k] = PDAMove{name(v.summaryReturn(f, s, a)), ""}
+					}
+				}
+			}
+		}
+		done = len(summaries)
+		if len(summaries) == n && len(frames) == m {
+			break
+		}
+	}
+
+	states, accept, stackAlphabet := mapset.NewSet(), mapset.NewSet(), mapset.NewSet()
+	for i, s := range summaries {
+		states.Add("s" + strconv.Itoa(i))
+		if v.summaryAccepts(s) {
+			accept.Add("s" + strconv.Itoa(i))
+		}
+	}
+	for j := range frames {
+		stackAlphabet.Add("g" + strconv.Itoa(j))
+	}
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		if m, ok := table[key{state, input, stackSymbol}]; ok {
+			return mapset.NewSet(m)
+		}
+		return nil
+	}
+	return NewVPA(states, v.calls, v.returns, v.internals, stackAlphabet, transition, start, accept)
+}
+
+// Given a VPA v, recognizing language L(v), v.Complement() returns a pointer to a new deterministic VPA recognizing the
+// strings over the alphabet of v which are not in L(v). It determinizes v and swaps accept and reject states.
+func (v VPA) Complement() (*VPA, error) {
+	d, err := v.Determinize()
+	if err != nil {
+		return nil, err
+	}
+	d.accept = d.states.Difference(d.accept)
+	return d, nil
+}
+
+//checks that v1 and v2 are valid and split the same alphabet the same way
+func checkVPAPair(v1, v2 *VPA) error {
+	ans, err := v1.CheckVPA()
+	if ans != true && err != nil {
+		return errors.New("gocompute/vpa: invalid VPA: " + err.Error())
+	}
+	ans, err = v2.CheckVPA()
+	if ans != true && err != nil {
+		return errors.New("gocompute/vpa: invalid VPA: " + err.Error())
+	}
+	if !v1.calls.Equal(v2.calls) || !v1.returns.Equal(v2.returns) || !v1.internals.Equal(v2.internals) {
+		return errors.New("gocompute/vpa: call, return and internal symbols of input VPAs must be equal")
+	}
+	return nil
+}
+
+//returns the product of v1 and v2, which runs both side by side, with the pairs
+//of states accepted by both if both is set and by either otherwise. states and
+//stack symbols are pairs written ("x","y"), quoted so that names containing commas
+//cannot run together
+func productVPA(v1, v2 *VPA, both bool) (*VPA, error) {
+	pair := func(x, y string) string {
+		return "(" + strconv.Quote(x) + "," + strconv.Quote(y) + ")"
+	}
+	//pairs by name, so the transition function can split them again
+	split := map[string][2]string{}
+	states, accept := mapset.NewSet(), mapset.NewSet()
+	for _, p := range sortedAlphabet(v1.states) {
+		for _, q := range sortedAlphabet(v2.states) {
+			states.Add(pair(p, q))
+			split[pair(p, q)] = [2]string{p, q}
+			a1, a2 := v1.accept.Contains(p), v2.accept.Contains(q)
+			if (both && a1 && a2) || (!both && (a1 || a2)) {
+				accept.Add(pair(p, q))
+			}
+		}
+	}
+	stackAlphabet := mapset.NewSet()
+	for _, x := range sortedAlphabet(v1.stackAlphabet) {
+		for _, y := range sortedAlphabet(v2.stackAlphabet) {
+			stackAlphabet.Add(pair(x, y))
+			split[pair(x, y)] = [2]string{x, y}
+		}
+	}
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		st, ok := split[state]
+		top := [2]string{"", ""}
+		if stackSymbol != "" {
+			top, ok = split[stackSymbol]
+		}
+		if !ok {
+			return nil
+		}
+		moves := mapset.NewSet()
+		for _, m1 := range v1.moves(st[0], input, top[0]) {
+			for _, m2 := range v2.moves(st[1], input, top[1]) {
+				push := ""
+				if v1.calls.Contains(input) {
+					push = pair(m1.Push, m2.Push)
+				}
+				moves.Add(PDAMove{pair(m1.State, m2.State), push})
+			}
+		}
+		return moves
+	}
+	return NewVPA(states, v1.calls, v1.returns, v1.internals, stackAlphabet, transition, pair(v1.start, v2.start), accept)
+}
+
+// Given VPAs v1 and v2 with the same call, return and internal symbols, which recognize languages L(v1) and L(v2)
+// respectively, v1.Intersection(v2) returns a pointer to a new VPA which recognizes the intersection of L(v1) and
+// L(v2). It runs v1 and v2 side by side, pushing pairs of their stack symbols, which works because both push and pop
+// on the same input symbols.
+func (v1 VPA) Intersection(v2 *VPA) (*VPA, error) {
+	if err := checkVPAPair(&v1, v2); err != nil {
+		return nil, err
+	}
+	return productVPA(&v1, v2, true)
+}
+
+// Given VPAs v1 and v2 with the same call, return and internal symbols, which recognize languages L(v1) and L(v2)
+// respectively, v1.Union(v2) returns a pointer to a new VPA which recognizes both L(v1) and L(v2). Both are
+// determinized first, so that neither run can get stuck while the other would accept.
+func (v1 VPA) Union(v2 *VPA) (*VPA, error) {
+	if err := checkVPAPair(&v1, v2); err != nil {
+		return nil, err
+	}
+	d1, err := v1.Determinize()
+	if err != nil {
+		return nil, err
+	}
+	d2, err := v2.Determinize()
+	if err != nil {
+		return nil, err
+	}
+	return productVPA(d1, d2, false)
+}
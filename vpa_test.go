@@ -0,0 +1,238 @@
+package gocompute
+
+import (
+	"github.com/jophish/golang-set"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var vpaAlphabet = []string{"<", ">", "a"}
+
+func makeWellMatchedVPA() (*VPA, error) {
+	//q0 is outside any tag and q1 inside one; a call from q0 pushes B so that its
+	//return knows to go back to q0
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case input == "<" && state == "q0":
+			return pdaMoves(PDAMove{"q1", "B"})
+		case input == "<" && state == "q1":
+			return pdaMoves(PDAMove{"q1", "X"})
+		case input == ">" && stackSymbol == "B":
+			return pdaMoves(PDAMove{"q0", ""})
+		case input == ">" && stackSymbol == "X":
+			return pdaMoves(PDAMove{"q1", ""})
+		case input == "a":
+			return pdaMoves(PDAMove{state, ""})
+		}
+		return nil
+	}
+	return NewVPA(mapset.NewSet("q0", "q1"), mapset.NewSet("<"), mapset.NewSet(">"), mapset.NewSet("a"), mapset.NewSet("B", "X"), transition, "q0", mapset.NewSet("q0"))
+}
+
+func makeEndsWithAVPA() (*VPA, error) {
+	//q0 guesses which a is the last symbol
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		if state != "q0" {
+			return nil
+		}
+		switch input {
+		case "<":
+			return pdaMoves(PDAMove{"q0", "X"})
+		case ">":
+			return pdaMoves(PDAMove{"q0", ""})
+		}
+		return pdaMoves(PDAMove{"q0", ""}, PDAMove{"q1", ""})
+	}
+	return NewVPA(mapset.NewSet("q0", "q1"), mapset.NewSet("<"), mapset.NewSet(">"), mapset.NewSet("a"), mapset.NewSet("X"), transition, "q0", mapset.NewSet("q1"))
+}
+
+func makeEmptyTagVPA() (*VPA, error) {
+	//q0 guesses a call pushing M whose return comes straight after it, and q2 accepts
+	//whatever follows
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case input == "<" && state == "q0":
+			return pdaMoves(PDAMove{"q0", "X"}, PDAMove{"q1", "M"})
+		case input == "<" && state == "q2":
+			return pdaMoves(PDAMove{"q2", "X"})
+		case input == ">" && state == "q1" && stackSymbol == "M":
+			return pdaMoves(PDAMove{"q2", ""})
+		case state == "q0" && stackSymbol != "M" && input != "<":
+			return pdaMoves(PDAMove{"q0", ""})
+		case state == "q2" && input != "<":
+			return pdaMoves(PDAMove{"q2", ""})
+		}
+		return nil
+	}
+	return NewVPA(mapset.NewSet("q0", "q1", "q2"), mapset.NewSet("<"), mapset.NewSet(">"), mapset.NewSet("a"), mapset.NewSet("X", "M"), transition, "q0", mapset.NewSet("q2"))
+}
+
+func wellMatched(w string) bool {
+	depth := 0
+	for _, r := range w {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+func endsWithA(w string) bool {
+	return strings.HasSuffix(w, "a")
+}
+
+func hasEmptyTag(w string) bool {
+	return strings.Contains(w, "<>")
+}
+
+var v1, v1err = makeWellMatchedVPA()
+var v2, v2err = makeEndsWithAVPA()
+var v3, v3err = makeEmptyTagVPA()
+
+var vpaTests = []struct {
+	v          *VPA
+	err        error
+	language   func(string) bool
+	descriptor string
+}{
+	{v1, v1err, wellMatched, "deterministic VPA accepting well-matched strings"},
+	{v2, v2err, endsWithA, "nondeterministic VPA accepting strings ending in an internal symbol"},
+	{v3, v3err, hasEmptyTag, "nondeterministic VPA accepting strings with a call followed straight away by its return"},
+}
+
+//checks that v recognizes the strings of length at most n on which language is true
+func checkVPALanguage(t *testing.T, v *VPA, n int, language func(string) bool, descriptor string) {
+	for _, w := range allStrings(vpaAlphabet, n) {
+		ans, err := v.Simulate(w)
+		if err != nil {
+			t.Error("On test: " + descriptor + ", while testing string " + w + ", error: " + err.Error())
+			return
+		}
+		if ans != language(w) {
+			t.Error("On test: " + descriptor + ", error: VPA should have answered " + strconv.FormatBool(language(w)) + " to string " + w)
+			return
+		}
+	}
+}
+
+func TestVPASimulate(t *testing.T) {
+	for _, test := range vpaTests {
+		if test.err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + test.err.Error())
+			t.FailNow()
+		}
+		checkVPALanguage(t, test.v, 6, test.language, test.descriptor)
+	}
+}
+
+func TestVPADeterminize(t *testing.T) {
+	for _, test := range vpaTests {
+		if test.v.IsDeterministic() != (test.v == v1) {
+			t.Error("On test: " + test.descriptor + ", error: wrong answer from IsDeterministic")
+		}
+		d, err := test.v.Determinize()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		if !d.IsDeterministic() {
+			t.Error("On test: determinized " + test.descriptor + ", error: result is not deterministic")
+		}
+		checkVPALanguage(t, d, 6, test.language, "determinized "+test.descriptor)
+
+		c, err := test.v.Complement()
+		if err != nil {
+			t.Error("On test: " + test.descriptor + ", error: " + err.Error())
+			continue
+		}
+		language := test.language
+		checkVPALanguage(t, c, 6, func(w string) bool { return !language(w) }, "complement of "+test.descriptor)
+	}
+}
+
+func TestVPAUnionIntersection(t *testing.T) {
+	for i, test1 := range vpaTests {
+		for _, test2 := range vpaTests[i+1:] {
+			l1, l2 := test1.language, test2.language
+			descriptor := test1.descriptor + " and " + test2.descriptor
+			u, err := test1.v.Union(test2.v)
+			if err != nil {
+				t.Error("On test: union of " + descriptor + ", error: " + err.Error())
+			} else {
+				checkVPALanguage(t, u, 4, func(w string) bool { return l1(w) || l2(w) }, "union of "+descriptor)
+			}
+			n, err := test1.v.Intersection(test2.v)
+			if err != nil {
+				t.Error("On test: intersection of " + descriptor + ", error: " + err.Error())
+			} else {
+				checkVPALanguage(t, n, 4, func(w string) bool { return l1(w) && l2(w) }, "intersection of "+descriptor)
+			}
+		}
+	}
+}
+
+func makeEvenAVPA(states [2]string) (*VPA, error) {
+	//the state flips on every a, starting from states[1]
+	transition := func(state, input, stackSymbol string) mapset.Set {
+		switch {
+		case input == "<":
+			return pdaMoves(PDAMove{state, "X"})
+		case input == "a" && state == states[0]:
+			return pdaMoves(PDAMove{states[1], ""})
+		case input == "a":
+			return pdaMoves(PDAMove{states[0], ""})
+		}
+		return pdaMoves(PDAMove{state, ""})
+	}
+	return NewVPA(mapset.NewSet(states[0], states[1]), mapset.NewSet("<"), mapset.NewSet(">"), mapset.NewSet("a"), mapset.NewSet("X"), transition, states[1], mapset.NewSet(states[1]))
+}
+
+func TestVPAProductNames(t *testing.T) {
+	//pairs of these states run together if their names are joined with a bare comma
+	e1, err1 := makeEvenAVPA([2]string{"p,q", "p"})
+	e2, err2 := makeEvenAVPA([2]string{"q", "q,q"})
+	if err1 != nil || err2 != nil {
+		t.Error("On test: VPAs with commas in their state names, error: could not build VPAs")
+		t.FailNow()
+	}
+	n, err := e1.Intersection(e2)
+	if err != nil {
+		t.Error("On test: intersection of VPAs with commas in their state names, error: " + err.Error())
+		t.FailNow()
+	}
+	if n.states.Cardinality() != 4 {
+		t.Error("On test: intersection of VPAs with commas in their state names, error: expected 4 states, got " + strconv.Itoa(n.states.Cardinality()))
+	}
+	checkVPALanguage(t, n, 4, func(w string) bool { return strings.Count(w, "a")%2 == 0 }, "intersection of VPAs with commas in their state names")
+}
+
+func TestCheckVPA(t *testing.T) {
+	pushesNothing := func(state, input, stackSymbol string) mapset.Set {
+		return pdaMoves(PDAMove{"q0", ""})
+	}
+	pushesOnReturn := func(state, input, stackSymbol string) mapset.Set {
+		return pdaMoves(PDAMove{"q0", "X"})
+	}
+	if _, err := NewVPA(mapset.NewSet("q0"), mapset.NewSet("<"), mapset.NewSet(">"), mapset.NewSet("a"), mapset.NewSet("X"), pushesNothing, "q0", mapset.NewSet()); err == nil {
+		t.Error("On test: call pushing nothing, error: invalid VPA should have been reported")
+	}
+	if _, err := NewVPA(mapset.NewSet("q0"), mapset.NewSet("<"), mapset.NewSet(">"), mapset.NewSet("a"), mapset.NewSet("X"), pushesOnReturn, "q0", mapset.NewSet()); err == nil {
+		t.Error("On test: return pushing a symbol, error: invalid VPA should have been reported")
+	}
+	if _, err := NewVPA(mapset.NewSet("q0"), mapset.NewSet("<"), mapset.NewSet("<"), mapset.NewSet("a"), mapset.NewSet("X"), pushesOnReturn, "q0", mapset.NewSet()); err == nil {
+		t.Error("On test: symbol both a call and a return, error: invalid VPA should have been reported")
+	}
+	other, _ := NewVPA(mapset.NewSet("q0"), mapset.NewSet("<"), mapset.NewSet(">", "a"), mapset.NewSet(), mapset.NewSet("X"), pushesOnReturn, "q0", mapset.NewSet())
+	if other != nil {
+		if _, err := v1.Intersection(other); err == nil {
+			t.Error("On test: intersection of VPAs splitting the alphabet differently, error: should have been reported")
+		}
+	}
+}